@@ -0,0 +1,1211 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// The checker runs after the resolver and reports type errors
+// before the program is executed. Annotations are optional, a
+// value the checker can not know the type of has type any and
+// is never reported, so programs without annotations are only
+// reported for mistakes that would fail at runtime anyway.
+// The checker does not change how a program runs. A type
+// followed by ? also allows nil, a trait can be used as a type
+// and the return annotation of an async function or a generator
+// is the type it resolves to or yields
+
+// Variable holds what the checker knows about a variable
+type Variable struct {
+	Type *Type
+	// Annotated variables keep their type on assignment
+	Annotated bool
+}
+
+type Checker struct {
+	Scopes []map[string]*Variable
+	// Structs holds the type of every struct declared in the
+	// program so annotations can name structs declared later
+	Structs map[string]*Type
+	// Traits holds the type of every trait declared in the program
+	Traits map[string]*Type
+	// Enums holds the type of every enum declared in the program
+	Enums map[string]*Type
+	// Assigned holds the names that are assigned somewhere, the
+	// type of such a variable is not known unless annotated
+	Assigned map[string]bool
+	// Globals holds the names declared in the global scope,
+	// which hide the natives of the same name
+	Globals map[string]bool
+	// CurrentReturn is the annotated return type of the
+	// function being checked
+	CurrentReturn *Type
+	// CurrentYield is the annotated type of the values
+	// yielded by the generator being checked
+	CurrentYield  *Type
+	CurrentStruct *Type
+	// Collecting is set during the first pass which only
+	// collects structs and assigned names
+	Collecting bool
+	Errors     []error
+}
+
+var _ expressions.ExprVisitor = (*Checker)(nil)
+var _ expressions.StmtVisitor = (*Checker)(nil)
+var _ expressions.PatternVisitor = (*Checker)(nil)
+
+func NewChecker() *Checker {
+	return &Checker{
+		Structs:  make(map[string]*Type),
+		Traits:   make(map[string]*Type),
+		Enums:    make(map[string]*Type),
+		Assigned: make(map[string]bool),
+		Globals:  make(map[string]bool),
+	}
+}
+
+// Error records a type error at the given token
+func (c *Checker) Error(token token.Token, message string) {
+	if c.Collecting {
+		return
+	}
+	c.Errors = append(c.Errors, fmt.Errorf("Type Error at '%v': %s", token.Lexeme, message))
+}
+
+// Check checks a program and returns every type error found.
+// The first pass collects the structs, the assigned names and
+// the globals
+func (c *Checker) Check(statements []expressions.Stmt) []error {
+	c.Collecting = true
+	c.CheckProgram(statements)
+	c.Collecting = false
+	c.CheckProgram(statements)
+	return c.Errors
+}
+
+// CheckProgram checks the statements of a program in a new
+// global scope
+func (c *Checker) CheckProgram(statements []expressions.Stmt) {
+	globals := make(map[string]*Variable)
+	for name, native := range nativeTypes {
+		// A global declared anywhere in the program replaces
+		// the native, even where it is used before it
+		if !c.Globals[name] {
+			globals[name] = &Variable{Type: native}
+		}
+	}
+	c.Scopes = []map[string]*Variable{globals}
+	c.CheckStatements(statements)
+}
+
+func (c *Checker) CheckStatements(statements []expressions.Stmt) {
+	for _, statement := range statements {
+		c.CheckStatement(statement)
+	}
+}
+
+func (c *Checker) CheckStatement(stmt expressions.Stmt) {
+	stmt.Accept(c)
+}
+
+// TypeOf checks an expression and gives its type
+func (c *Checker) TypeOf(expr expressions.Expr) *Type {
+	result, _ := expr.Accept(c)
+	if t, ok := result.(*Type); ok {
+		return t
+	}
+	return Any
+}
+
+func (c *Checker) CheckPattern(pattern expressions.Pattern) {
+	pattern.Accept(c)
+}
+
+func (c *Checker) BeginScope() {
+	c.Scopes = append(c.Scopes, make(map[string]*Variable))
+}
+
+func (c *Checker) EndScope() {
+	c.Scopes = c.Scopes[:len(c.Scopes)-1]
+}
+
+// Declare gives a variable a type in the innermost scope
+func (c *Checker) Declare(name token.Token, t *Type, annotated bool) {
+	if c.Collecting && len(c.Scopes) == 1 {
+		c.Globals[name.Lexeme] = true
+	}
+	c.Scopes[len(c.Scopes)-1][name.Lexeme] = &Variable{Type: t, Annotated: annotated}
+}
+
+// DeclareInferred declares a variable without an annotation,
+// its type is the type of its value unless it is assigned to
+func (c *Checker) DeclareInferred(name token.Token, t *Type) {
+	if c.Assigned[name.Lexeme] {
+		t = Any
+	}
+	c.Declare(name, t, false)
+}
+
+// Lookup finds a variable, nil is returned for names the
+// checker does not know
+func (c *Checker) Lookup(name string) *Variable {
+	for i := len(c.Scopes) - 1; i >= 0; i-- {
+		if variable, ok := c.Scopes[i][name]; ok {
+			return variable
+		}
+	}
+	return nil
+}
+
+// ResolveType gives the type named by an annotation, nil is
+// returned when there is no annotation
+func (c *Checker) ResolveType(annotation *expressions.TypeAnnotation) *Type {
+	if annotation == nil {
+		return nil
+	}
+
+	name := annotation.Name.Lexeme
+	t, ok := typeNames[name]
+	if structType, isStruct := c.Structs[name]; !ok && isStruct {
+		t, ok = structType.Instance(), true
+	}
+	if trait, isTrait := c.Traits[name]; !ok && isTrait {
+		t, ok = trait, true
+	}
+	if enum, isEnum := c.Enums[name]; !ok && isEnum {
+		t, ok = enum.Value(), true
+	}
+	if !ok {
+		c.Error(annotation.Name, "Unknown type "+name+".")
+		return Any
+	}
+
+	if annotation.Nullable {
+		return t.OrNil()
+	}
+	return t
+}
+
+// orAny gives any for a missing annotation
+func orAny(t *Type) *Type {
+	if t == nil {
+		return Any
+	}
+	return t
+}
+
+// FunctionType builds the type of a function declaration
+func (c *Checker) FunctionType(function *expressions.Function) *Type {
+	params := make([]*Type, len(function.Params))
+	minArity := 0
+	for index := range function.Params {
+		params[index] = orAny(c.ResolveType(function.ParamTypes[index]))
+		if function.Defaults[index] == nil && !(function.Variadic && index == len(function.Params)-1) {
+			minArity = index + 1
+		}
+	}
+
+	maxArity := len(function.Params)
+	if function.Variadic {
+		maxArity = interpreter.VARIADIC
+		// The rest parameter always holds a list
+		params = params[:len(params)-1]
+	}
+
+	// The annotation of a generator is the type of its values
+	result := orAny(c.ResolveType(function.ReturnType))
+	if function.Generator {
+		result = &Type{Kind: GeneratorKind, Yields: result}
+	}
+	// and the annotation of an async function is the type
+	// of the value its promise resolves to
+	if function.Async {
+		result = &Type{Kind: PromiseKind, Resolves: result}
+	}
+	return NewFunction(params, minArity, maxArity, result)
+}
+
+// CheckFunction checks the body of a function
+func (c *Checker) CheckFunction(function *expressions.Function, t *Type) {
+	enclosingReturn, enclosingYield := c.CurrentReturn, c.CurrentYield
+	c.CurrentReturn, c.CurrentYield = nil, nil
+	if function.ReturnType != nil {
+		if function.Generator {
+			c.CurrentYield = t.Return.Yields
+		} else if function.Async {
+			c.CurrentReturn = t.Return.Resolves
+		} else {
+			c.CurrentReturn = t.Return
+		}
+	}
+	defer func() { c.CurrentReturn, c.CurrentYield = enclosingReturn, enclosingYield }()
+
+	c.BeginScope()
+	for index, param := range function.Params {
+		if function.Variadic && index == len(function.Params)-1 {
+			c.Declare(param, List, true)
+			continue
+		}
+
+		annotated := function.ParamTypes[index] != nil
+		if function.Defaults[index] != nil {
+			value := c.TypeOf(function.Defaults[index])
+			if !Assignable(value, t.Params[index]) {
+				c.Error(param, fmt.Sprintf("Default value of type %v does not match parameter type %v.", value, t.Params[index]))
+			}
+		}
+		if annotated {
+			c.Declare(param, t.Params[index], true)
+		} else {
+			c.DeclareInferred(param, Any)
+		}
+	}
+
+	c.CheckStatements(function.Body)
+	c.EndScope()
+}
+
+// Statements
+
+func (c *Checker) VisitBlockStmt(stmt *expressions.Block) (interface{}, error) {
+	c.BeginScope()
+	c.CheckStatements(stmt.Statements)
+	c.EndScope()
+	return nil, nil
+}
+
+func (c *Checker) VisitClassStmt(stmt *expressions.Class) (interface{}, error) {
+	structType := &Type{
+		Kind:          StructKind,
+		Name:          stmt.Name.Lexeme,
+		Fields:        make(map[string]*Type),
+		Methods:       make(map[string]*Type),
+		StaticMethods: make(map[string]*Type),
+		Getters:       make(map[string]*Type),
+		Setters:       make(map[string]*Type),
+	}
+	c.Structs[stmt.Name.Lexeme] = structType
+	for _, trait := range stmt.Traits {
+		structType.Traits = append(structType.Traits, trait.Name.Lexeme)
+	}
+
+	for index, field := range stmt.Fields {
+		if _, ok := structType.Fields[field.Lexeme]; ok {
+			c.Error(field, "Field "+field.Lexeme+" is already declared.")
+		}
+		structType.Fields[field.Lexeme] = c.ResolveType(stmt.FieldTypes[index])
+	}
+
+	structType.Constructor = NewFunction(nil, 0, 0, Any)
+	for _, method := range stmt.Methods {
+		methodType := c.FunctionType(method)
+		if method.Name.Lexeme == interpreter.CLASS_CONSTRUCTOR_NAME {
+			structType.Constructor = methodType
+		} else {
+			structType.Methods[method.Name.Lexeme] = methodType
+		}
+	}
+	staticTypes := make([]*Type, len(stmt.StaticMethods))
+	for index, method := range stmt.StaticMethods {
+		staticTypes[index] = c.FunctionType(method)
+		structType.StaticMethods[method.Name.Lexeme] = staticTypes[index]
+	}
+	getterTypes := make([]*Type, len(stmt.Getters))
+	for index, getter := range stmt.Getters {
+		getterTypes[index] = c.FunctionType(getter)
+		structType.Getters[getter.Name.Lexeme] = getterTypes[index].Return
+	}
+	setterTypes := make([]*Type, len(stmt.Setters))
+	for index, setter := range stmt.Setters {
+		setterTypes[index] = c.FunctionType(setter)
+		structType.Setters[setter.Name.Lexeme] = setterTypes[index].Params[0]
+	}
+	c.DeclareInferred(stmt.Name, structType)
+
+	for index, method := range stmt.StaticMethods {
+		c.CheckFunction(method, staticTypes[index])
+	}
+
+	enclosingStruct := c.CurrentStruct
+	c.CurrentStruct = structType
+	for _, method := range stmt.Methods {
+		if method.Name.Lexeme == interpreter.CLASS_CONSTRUCTOR_NAME {
+			c.CheckFunction(method, structType.Constructor)
+		} else {
+			c.CheckFunction(method, structType.Methods[method.Name.Lexeme])
+		}
+	}
+	for index, getter := range stmt.Getters {
+		c.CheckFunction(getter, getterTypes[index])
+	}
+	for index, setter := range stmt.Setters {
+		c.CheckFunction(setter, setterTypes[index])
+	}
+	c.CurrentStruct = enclosingStruct
+
+	return nil, nil
+}
+
+func (c *Checker) VisitTraitStmt(stmt *expressions.Trait) (interface{}, error) {
+	trait := &Type{
+		Kind:    TraitKind,
+		Name:    stmt.Name.Lexeme,
+		Methods: make(map[string]*Type),
+	}
+	for _, method := range stmt.Methods {
+		trait.Methods[method.Name.Lexeme] = c.FunctionType(method)
+	}
+	c.Traits[stmt.Name.Lexeme] = trait
+
+	// The trait itself is a runtime value used with implements
+	c.DeclareInferred(stmt.Name, Any)
+	return nil, nil
+}
+
+func (c *Checker) VisitEnumStmt(stmt *expressions.Enum) (interface{}, error) {
+	enum := &Type{
+		Kind:          EnumKind,
+		Name:          stmt.Name.Lexeme,
+		Variants:      make(map[string]*Type),
+		VariantFields: make(map[string][]string),
+	}
+	for index, variant := range stmt.Variants {
+		fields := stmt.Fields[index]
+		if fields == nil {
+			enum.Variants[variant.Lexeme] = enum.Value()
+			continue
+		}
+
+		params := make([]*Type, len(fields))
+		names := make([]string, len(fields))
+		for position, field := range fields {
+			params[position] = Any
+			names[position] = field.Lexeme
+		}
+		enum.Variants[variant.Lexeme] = NewFunction(params, len(fields), len(fields), enum.Value())
+		enum.VariantFields[variant.Lexeme] = names
+	}
+	c.Enums[stmt.Name.Lexeme] = enum
+
+	c.DeclareInferred(stmt.Name, enum)
+	return nil, nil
+}
+
+func (c *Checker) VisitExprStatementStmt(stmt *expressions.ExprStatement) (interface{}, error) {
+	c.TypeOf(stmt.Expression)
+	return nil, nil
+}
+
+func (c *Checker) VisitPrintStatementStmt(stmt *expressions.PrintStatement) (interface{}, error) {
+	c.TypeOf(stmt.Expression)
+	return nil, nil
+}
+
+func (c *Checker) VisitReturnStmt(stmt *expressions.Return) (interface{}, error) {
+	value := Nil
+	if stmt.Value != nil {
+		value = c.TypeOf(stmt.Value)
+	}
+
+	if c.CurrentReturn != nil && !Assignable(value, c.CurrentReturn) {
+		c.Error(stmt.Keyword, fmt.Sprintf("Can not return %v from a function returning %v.", value, c.CurrentReturn))
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitYieldStmt(stmt *expressions.Yield) (interface{}, error) {
+	value := Nil
+	if stmt.Value != nil {
+		value = c.TypeOf(stmt.Value)
+	}
+
+	if c.CurrentYield != nil && !Assignable(value, c.CurrentYield) {
+		c.Error(stmt.Keyword, fmt.Sprintf("Can not yield %v from a generator yielding %v.", value, c.CurrentYield))
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitWhileStatementStmt(stmt *expressions.WhileStatement) (interface{}, error) {
+	c.TypeOf(stmt.Condition)
+	c.CheckStatement(stmt.Body)
+	return nil, nil
+}
+
+func (c *Checker) VisitSelectStmt(stmt *expressions.Select) (interface{}, error) {
+	for _, selectCase := range stmt.Cases {
+		channel := c.TypeOf(selectCase.Channel)
+		if channel.IsKnown() && (channel.Kind != ChannelKind || channel.Nullable) {
+			c.Error(selectCase.Operation, fmt.Sprintf("Can only select on channels, got %v.", channel))
+		}
+		if selectCase.Value != nil {
+			c.TypeOf(selectCase.Value)
+		}
+	}
+
+	for _, selectCase := range stmt.Cases {
+		c.BeginScope()
+		if selectCase.Name != nil {
+			c.DeclareInferred(*selectCase.Name, Any)
+		}
+		c.CheckStatement(selectCase.Body)
+		c.EndScope()
+	}
+	if stmt.Default != nil {
+		c.CheckStatement(stmt.Default)
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitForInStmt(stmt *expressions.ForIn) (interface{}, error) {
+	iterable := c.TypeOf(stmt.Iterable)
+	element := Any
+	switch {
+	case iterable.Nullable:
+		c.Error(stmt.Keyword, fmt.Sprintf("Can not iterate over a value of type %v.", iterable))
+	case iterable.Kind == StringKind:
+		element = String
+	case iterable.Kind == GeneratorKind:
+		element = iterable.Yields
+	case iterable.Kind != AnyKind && iterable.Kind != ListKind && iterable.Kind != MapKind && iterable.Kind != ChannelKind:
+		c.Error(stmt.Keyword, fmt.Sprintf("Can not iterate over a value of type %v.", iterable))
+	}
+
+	c.BeginScope()
+	c.DeclareInferred(stmt.Name, element)
+	c.CheckStatement(stmt.Body)
+	c.EndScope()
+	return nil, nil
+}
+
+func (c *Checker) VisitVarStmt(stmt *expressions.Var) (interface{}, error) {
+	value := Nil
+	if stmt.Initializer != nil {
+		value = c.TypeOf(stmt.Initializer)
+	}
+
+	annotation := c.ResolveType(stmt.Type)
+	if annotation == nil {
+		c.DeclareInferred(stmt.Name, value)
+		return nil, nil
+	}
+
+	if !Assignable(value, annotation) {
+		c.Error(stmt.Name, fmt.Sprintf("Can not initialize %v of type %v with %v.", stmt.Name.Lexeme, annotation, value))
+	}
+	c.Declare(stmt.Name, annotation, true)
+	return nil, nil
+}
+
+func (c *Checker) VisitDestructureStmt(stmt *expressions.Destructure) (interface{}, error) {
+	c.TypeOf(stmt.Initializer)
+	c.CheckPattern(stmt.Pattern)
+	return nil, nil
+}
+
+func (c *Checker) VisitMultiAssignStmt(stmt *expressions.MultiAssign) (interface{}, error) {
+	values := make([]*Type, len(stmt.Values))
+	for index, value := range stmt.Values {
+		values[index] = c.TypeOf(value)
+	}
+
+	for index, target := range stmt.Targets {
+		// A single list value is unpacked into the targets
+		value := Any
+		if len(values) == len(stmt.Targets) {
+			value = values[index]
+		}
+
+		switch t := target.(type) {
+		case *expressions.Variable:
+			c.CheckAssignment(t.Name, value)
+		case *expressions.Get:
+			c.CheckFieldAssignment(c.TypeOf(t.Object), t.Name, value)
+		case *expressions.Index:
+			c.CheckIndexAssignment(t.Bracket, c.TypeOf(t.Object), c.TypeOf(t.Index))
+		}
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitIfStmt(stmt *expressions.If) (interface{}, error) {
+	c.TypeOf(stmt.Condition)
+	c.CheckStatement(stmt.ThenBranch)
+	if stmt.ElseBranch != nil {
+		c.CheckStatement(stmt.ElseBranch)
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitFunctionStmt(stmt *expressions.Function) (interface{}, error) {
+	t := c.FunctionType(stmt)
+	// Declared before the body so it can call itself
+	c.DeclareInferred(stmt.Name, t)
+	c.CheckFunction(stmt, t)
+	return nil, nil
+}
+
+func (c *Checker) VisitMatchStmt(stmt *expressions.Match) (interface{}, error) {
+	c.TypeOf(stmt.Value)
+
+	for _, arm := range stmt.Arms {
+		c.BeginScope()
+		for _, pattern := range arm.Patterns {
+			c.CheckPattern(pattern)
+		}
+		if arm.Guard != nil {
+			c.TypeOf(arm.Guard)
+		}
+		c.CheckStatement(arm.Body)
+		c.EndScope()
+	}
+	return nil, nil
+}
+
+// Assignments
+
+// CheckAssignment checks the type of a value assigned to a
+// variable, only annotated variables are checked
+func (c *Checker) CheckAssignment(name token.Token, value *Type) {
+	c.Assigned[name.Lexeme] = true
+
+	variable := c.Lookup(name.Lexeme)
+	if variable == nil || !variable.Annotated {
+		return
+	}
+	if !Assignable(value, variable.Type) {
+		c.Error(name, fmt.Sprintf("Can not assign %v to %s of type %v.", value, name.Lexeme, variable.Type))
+	}
+}
+
+// CheckFieldAssignment checks the type of a value assigned to
+// a declared field of a struct instance
+func (c *Checker) CheckFieldAssignment(object *Type, name token.Token, value *Type) {
+	if structType := c.StructOf(object); structType != nil {
+		if setter, ok := structType.Setters[name.Lexeme]; ok {
+			if !Assignable(value, setter) {
+				c.Error(name, fmt.Sprintf("Can not assign %v to property %s of type %v.", value, name.Lexeme, setter))
+			}
+			return
+		}
+		if _, ok := structType.Getters[name.Lexeme]; ok {
+			c.Error(name, "Can not set property "+name.Lexeme+" which only has a getter.")
+			return
+		}
+	}
+
+	field := c.FieldType(object, name.Lexeme)
+	if field != nil && !Assignable(value, field) {
+		c.Error(name, fmt.Sprintf("Can not assign %v to field %s of type %v.", value, name.Lexeme, field))
+	}
+}
+
+// FieldType gives the declared type of a field of an
+// instance, nil is returned for fields that are not declared
+func (c *Checker) FieldType(object *Type, name string) *Type {
+	structType := c.StructOf(object)
+	if structType == nil {
+		return nil
+	}
+	return structType.Fields[name]
+}
+
+// StructOf gives the struct type of an instance type, nil is
+// returned for any other type
+func (c *Checker) StructOf(object *Type) *Type {
+	if object.Kind != InstanceKind {
+		return nil
+	}
+	return c.Structs[object.Name]
+}
+
+// Expressions
+
+func (c *Checker) VisitAssignExpr(expr *expressions.Assign) (interface{}, error) {
+	value := c.TypeOf(expr.Value)
+	c.CheckAssignment(expr.Name, value)
+	return value, nil
+}
+
+func (c *Checker) VisitLogicalExpr(expr *expressions.Logical) (interface{}, error) {
+	left := c.TypeOf(expr.Left)
+	right := c.TypeOf(expr.Right)
+
+	if expr.Operator.Type == token.QUESTION_QUESTION {
+		if left.Kind == NilKind {
+			return right, nil
+		}
+		return Join(left.NonNil(), right), nil
+	}
+	return Join(left, right), nil
+}
+
+func (c *Checker) VisitConditionalExpr(expr *expressions.Conditional) (interface{}, error) {
+	c.TypeOf(expr.Condition)
+	return Join(c.TypeOf(expr.ThenBranch), c.TypeOf(expr.ElseBranch)), nil
+}
+
+func (c *Checker) VisitBinaryExpr(expr *expressions.Binary) (interface{}, error) {
+	left := c.TypeOf(expr.Left)
+	right := c.TypeOf(expr.Right)
+	operator := expr.Operator
+
+	// Operators on an instance call its special methods
+	if left.Kind == InstanceKind || left.Kind == TraitKind {
+		return c.OverloadedType(operator, left, right), nil
+	}
+
+	switch operator.Type {
+	case token.EQUAL_EQUAL, token.BANG_EQUAL:
+		return Bool, nil
+	}
+
+	// Times and durations have arithmetic and comparisons of
+	// their own
+	if left.Kind == TimeKind || left.Kind == DurationKind || right.Kind == TimeKind || right.Kind == DurationKind {
+		if !left.IsKnown() || !right.IsKnown() {
+			return Any, nil
+		}
+		result, ok := timeArithmeticType(operator.Type, left, right)
+		if !ok {
+			c.Error(operator, fmt.Sprintf("Operator '%s' can not be applied to %v and %v.", operator.Lexeme, left, right))
+			return Any, nil
+		}
+		return result, nil
+	}
+
+	switch operator.Type {
+	case token.PLUS:
+		if left.Kind == StringKind || right.Kind == StringKind {
+			if (left.IsKnown() && left.Kind != StringKind) || (right.IsKnown() && right.Kind != StringKind) {
+				c.Error(operator, fmt.Sprintf("Can not add %v and %v.", left, right))
+				return Any, nil
+			}
+			return String, nil
+		}
+		if !c.CheckNumbers(operator, left, right) {
+			return Any, nil
+		}
+		return arithmeticType(operator.Type, left, right), nil
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		c.CheckNumbers(operator, left, right)
+		return Bool, nil
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		if left.Kind == FloatKind || right.Kind == FloatKind {
+			c.Error(operator, "Operands of '"+operator.Lexeme+"' must be integers.")
+			return Any, nil
+		}
+		if !c.CheckNumbers(operator, left, right) {
+			return Any, nil
+		}
+		return Int, nil
+	}
+
+	if !c.CheckNumbers(operator, left, right) {
+		return Any, nil
+	}
+	return arithmeticType(operator.Type, left, right), nil
+}
+
+// OverloadedType checks an operator with an instance on its
+// left against the special method it calls
+func (c *Checker) OverloadedType(operator token.Token, left, right *Type) *Type {
+	comparison := false
+	switch operator.Type {
+	case token.EQUAL_EQUAL, token.BANG_EQUAL, token.LESS, token.LESS_EQUAL, token.GREATER, token.GREATER_EQUAL:
+		comparison = true
+	}
+
+	name, ok := interpreter.OperatorMethod(operator.Type)
+	if !ok {
+		c.Error(operator, fmt.Sprintf("Operator '%s' can not be applied to %v.", operator.Lexeme, left))
+		return Any
+	}
+
+	method := c.MethodType(left, name)
+	if method == nil {
+		if left.Kind == TraitKind {
+			return Any
+		}
+		// Every instance can be compared for equality
+		if operator.Type == token.EQUAL_EQUAL || operator.Type == token.BANG_EQUAL {
+			return Bool
+		}
+		c.Error(operator, fmt.Sprintf("%v does not define %s for '%s'.", left, name, operator.Lexeme))
+		return Any
+	}
+
+	if len(method.Params) > 0 && !Assignable(right, method.Params[0]) {
+		c.Error(operator, fmt.Sprintf("Operand of type %v does not match parameter type %v of %s.", right, method.Params[0], name))
+	}
+	if comparison {
+		return Bool
+	}
+	return method.Return
+}
+
+// MethodType gives the type of a method of an instance or trait
+// type, nil is returned when the method is not known
+func (c *Checker) MethodType(object *Type, name string) *Type {
+	switch object.Kind {
+	case InstanceKind:
+		if structType := c.StructOf(object); structType != nil {
+			return structType.Methods[name]
+		}
+	case TraitKind:
+		return c.Traits[object.Name].Methods[name]
+	}
+	return nil
+}
+
+// CheckNumbers reports operands that are known not to be
+// numbers, it returns true when both operands are numbers
+func (c *Checker) CheckNumbers(operator token.Token, left, right *Type) bool {
+	for _, operand := range []*Type{left, right} {
+		if operand.IsKnown() && !operand.IsNumber() {
+			c.Error(operator, fmt.Sprintf("Operand of '%s' must be a number, got %v.", operator.Lexeme, operand))
+			return false
+		}
+	}
+	return left.IsNumber() && right.IsNumber()
+}
+
+func (c *Checker) VisitCallExpr(expr *expressions.Call) (interface{}, error) {
+	callee := c.TypeOf(expr.Callee)
+
+	// An optional method call obj?.method() is nil when the
+	// object is nil, the call itself is checked on the method
+	if get, ok := expr.Callee.(*expressions.Get); ok && get.Optional {
+		if callee.Kind == NilKind {
+			for _, argument := range expr.Arguments {
+				c.TypeOf(argument)
+			}
+			for _, value := range expr.KeywordValues {
+				c.TypeOf(value)
+			}
+			return Nil, nil
+		}
+		return c.CallType(expr, callee.NonNil()).OrNil(), nil
+	}
+	return c.CallType(expr, callee), nil
+}
+
+// CallType checks the arguments of a call and gives the type
+// of its result
+func (c *Checker) CallType(expr *expressions.Call, callee *Type) *Type {
+	arguments := make([]*Type, len(expr.Arguments))
+	spread := false
+	for index, argument := range expr.Arguments {
+		arguments[index] = c.TypeOf(argument)
+		if _, ok := argument.(*expressions.Spread); ok {
+			spread = true
+		}
+	}
+	for _, value := range expr.KeywordValues {
+		c.TypeOf(value)
+	}
+
+	if !callee.IsCallable() {
+		c.Error(expr.Paren, fmt.Sprintf("Can not call a value of type %v.", callee))
+		return Any
+	}
+
+	function := callee
+	result := Any
+	switch callee.Kind {
+	case AnyKind:
+		return Any
+	case StructKind:
+		function = callee.Constructor
+		result = callee.Instance()
+	default:
+		result = callee.Return
+	}
+
+	// Arguments given by keyword or spread from a list can
+	// only be checked at runtime
+	if spread || len(expr.Keywords) > 0 {
+		return result
+	}
+
+	if message := arityError(function, len(arguments)); message != "" {
+		c.Error(expr.Paren, message)
+		return result
+	}
+	for index, argument := range arguments {
+		if index < len(function.Params) && !Assignable(argument, function.Params[index]) {
+			c.Error(expr.Paren, fmt.Sprintf("Argument %d of type %v does not match parameter type %v.", index+1, argument, function.Params[index]))
+		}
+	}
+	return result
+}
+
+// arityError describes a call with the wrong number of
+// arguments the way the interpreter does
+func arityError(function *Type, count int) string {
+	min, max := function.MinArity, function.MaxArity
+	switch {
+	case min == max && count != min:
+		return fmt.Sprintf("Expected %d arguments but got %d.", min, count)
+	case count < min:
+		return fmt.Sprintf("Expected at least %d arguments but got %d.", min, count)
+	case max != interpreter.VARIADIC && count > max:
+		return fmt.Sprintf("Expected at most %d arguments but got %d.", max, count)
+	}
+	return ""
+}
+
+func (c *Checker) VisitSpreadExpr(expr *expressions.Spread) (interface{}, error) {
+	c.TypeOf(expr.Expression)
+	return Any, nil
+}
+
+// VisitSpawnExpr checks the call of a task, the result of the
+// call is only available through join
+func (c *Checker) VisitSpawnExpr(expr *expressions.Spawn) (interface{}, error) {
+	c.TypeOf(expr.Call)
+	return Task, nil
+}
+
+// VisitAwaitExpr gives the type a promise resolves to, any
+// other value is the result of await itself
+func (c *Checker) VisitAwaitExpr(expr *expressions.Await) (interface{}, error) {
+	value := c.TypeOf(expr.Value)
+	if value.Kind != PromiseKind {
+		return value, nil
+	}
+	if value.Nullable {
+		return value.Resolves.OrNil(), nil
+	}
+	return value.Resolves, nil
+}
+
+func (c *Checker) VisitGetExpr(expr *expressions.Get) (interface{}, error) {
+	object := c.TypeOf(expr.Object)
+	if expr.Optional {
+		if object.Kind == NilKind {
+			return Nil, nil
+		}
+		object = object.NonNil()
+	}
+
+	switch object.Kind {
+	case NilKind, BoolKind, IntKind, FloatKind, NumberKind:
+		c.Error(expr.Name, fmt.Sprintf("Values of type %v have no properties.", object))
+		return Any, nil
+	case StringKind:
+		method, ok := stringMethodTypes[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Strings have no method "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, method), nil
+	case InstanceKind:
+		structType := c.StructOf(object)
+		if structType == nil {
+			return Any, nil
+		}
+		if getter, ok := structType.Getters[expr.Name.Lexeme]; ok {
+			return c.OptionalResult(expr, getter), nil
+		}
+		if field, ok := structType.Fields[expr.Name.Lexeme]; ok {
+			return c.OptionalResult(expr, field), nil
+		}
+		if method, ok := structType.Methods[expr.Name.Lexeme]; ok {
+			return c.OptionalResult(expr, method), nil
+		}
+	case StructKind:
+		method, ok := object.StaticMethods[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Struct "+object.Name+" has no static method "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, method), nil
+	case TraitKind:
+		if method, ok := c.Traits[object.Name].Methods[expr.Name.Lexeme]; ok {
+			return c.OptionalResult(expr, method), nil
+		}
+	case EnumKind:
+		variant, ok := object.Variants[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Enum "+object.Name+" has no variant "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, variant), nil
+	case EnumValueKind:
+		return c.OptionalResult(expr, c.EnumPropertyType(object, expr.Name)), nil
+	case GeneratorKind:
+		switch expr.Name.Lexeme {
+		case "next":
+			return c.OptionalResult(expr, NewFunction(nil, 0, 0, object.Yields.OrNil())), nil
+		case "close":
+			return c.OptionalResult(expr, NewFunction(nil, 0, 0, Nil)), nil
+		}
+		c.Error(expr.Name, "Generators have no method "+expr.Name.Lexeme+".")
+		return Any, nil
+	case ChannelKind:
+		method, ok := channelMethodTypes[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Channels have no method "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, method), nil
+	case TaskKind:
+		method, ok := taskMethodTypes[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Tasks have no method "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, method), nil
+	case PromiseKind:
+		c.Error(expr.Name, "Promises have no properties, await the promise first.")
+		return Any, nil
+	case TimeKind:
+		property, ok := timePropertyTypes[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Times have no property "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, property), nil
+	case DurationKind:
+		property, ok := durationPropertyTypes[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Durations have no property "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, property), nil
+	case RegexKind:
+		property, ok := regexPropertyTypes[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Regexes have no property "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, property), nil
+	case ModuleKind:
+		member, ok := object.Members[expr.Name.Lexeme]
+		if !ok {
+			c.Error(expr.Name, "Module "+object.Name+" has no member "+expr.Name.Lexeme+".")
+			return Any, nil
+		}
+		return c.OptionalResult(expr, member), nil
+	}
+	return Any, nil
+}
+
+// EnumPropertyType gives the type of a property of an enum
+// value. A field is only reported when no variant has it
+func (c *Checker) EnumPropertyType(object *Type, name token.Token) *Type {
+	switch name.Lexeme {
+	case "name":
+		return String
+	case "ordinal":
+		return Int
+	case "payload":
+		return List
+	}
+
+	enum, ok := c.Enums[object.Name]
+	if !ok {
+		return Any
+	}
+	for _, fields := range enum.VariantFields {
+		for _, field := range fields {
+			if field == name.Lexeme {
+				return Any
+			}
+		}
+	}
+	c.Error(name, "Enum "+object.Name+" has no property "+name.Lexeme+".")
+	return Any
+}
+
+// OptionalResult gives the type of an optional get which may
+// be nil when its object is nil
+func (c *Checker) OptionalResult(expr *expressions.Get, t *Type) *Type {
+	if expr.Optional {
+		return t.OrNil()
+	}
+	return t
+}
+
+func (c *Checker) VisitSetExpr(expr *expressions.Set) (interface{}, error) {
+	value := c.TypeOf(expr.Value)
+	c.CheckFieldAssignment(c.TypeOf(expr.Object), expr.Name, value)
+	return value, nil
+}
+
+func (c *Checker) VisitIndexExpr(expr *expressions.Index) (interface{}, error) {
+	object := c.TypeOf(expr.Object)
+	index := c.TypeOf(expr.Index)
+
+	switch object.Kind {
+	case AnyKind, TraitKind:
+		return Any, nil
+	case ListKind:
+		c.CheckPosition(expr.Bracket, index)
+		return Any, nil
+	case MapKind:
+		return Any, nil
+	case StringKind:
+		c.CheckPosition(expr.Bracket, index)
+		return String, nil
+	case InstanceKind:
+		method := c.MethodType(object, interpreter.INDEX_METHOD)
+		if method == nil {
+			c.Error(expr.Bracket, fmt.Sprintf("%v does not define %s.", object, interpreter.INDEX_METHOD))
+			return Any, nil
+		}
+		return method.Return, nil
+	}
+
+	c.Error(expr.Bracket, fmt.Sprintf("Values of type %v can not be indexed.", object))
+	return Any, nil
+}
+
+func (c *Checker) VisitSetIndexExpr(expr *expressions.SetIndex) (interface{}, error) {
+	value := c.TypeOf(expr.Value)
+	c.CheckIndexAssignment(expr.Bracket, c.TypeOf(expr.Object), c.TypeOf(expr.Index))
+	return value, nil
+}
+
+// CheckIndexAssignment checks that an object can be assigned by index
+func (c *Checker) CheckIndexAssignment(bracket token.Token, object, index *Type) {
+	switch object.Kind {
+	case AnyKind, TraitKind, MapKind:
+	case ListKind:
+		c.CheckPosition(bracket, index)
+	case InstanceKind:
+		if c.MethodType(object, interpreter.SET_INDEX_METHOD) == nil {
+			c.Error(bracket, fmt.Sprintf("%v does not define %s.", object, interpreter.SET_INDEX_METHOD))
+		}
+	default:
+		c.Error(bracket, fmt.Sprintf("Values of type %v can not be assigned by index.", object))
+	}
+}
+
+// CheckPosition reports an index into a list or a string that
+// is known not to be an integer
+func (c *Checker) CheckPosition(bracket token.Token, index *Type) {
+	if index.IsKnown() && index.Kind != IntKind && index.Kind != NumberKind {
+		c.Error(bracket, fmt.Sprintf("Index must be an integer, got %v.", index))
+	}
+}
+
+func (c *Checker) VisitThisExpr(expr *expressions.This) (interface{}, error) {
+	if c.CurrentStruct == nil {
+		return Any, nil
+	}
+	return c.CurrentStruct.Instance(), nil
+}
+
+func (c *Checker) VisitGroupingExpr(expr *expressions.Grouping) (interface{}, error) {
+	return c.TypeOf(expr.Expression), nil
+}
+
+func (c *Checker) VisitLiteralExpr(expr *expressions.Literal) (interface{}, error) {
+	return literalType(expr.Value), nil
+}
+
+func (c *Checker) VisitUnaryExpr(expr *expressions.Unary) (interface{}, error) {
+	right := c.TypeOf(expr.Right)
+
+	switch expr.Operator.Type {
+	case token.BANG:
+		return Bool, nil
+	case token.TILDE:
+		if right.IsKnown() && right.Kind != IntKind && right.Kind != NumberKind {
+			c.Error(expr.Operator, fmt.Sprintf("Operand of '~' must be an integer, got %v.", right))
+			return Any, nil
+		}
+		return Int, nil
+	}
+
+	if right.IsKnown() && !right.IsNumber() {
+		c.Error(expr.Operator, fmt.Sprintf("Operand of '-' must be a number, got %v.", right))
+		return Any, nil
+	}
+	return right.NonNil(), nil
+}
+
+func (c *Checker) VisitVariableExpr(expr *expressions.Variable) (interface{}, error) {
+	if variable := c.Lookup(expr.Name.Lexeme); variable != nil {
+		return variable.Type, nil
+	}
+	return Any, nil
+}
+
+func (c *Checker) VisitListLiteralExpr(expr *expressions.ListLiteral) (interface{}, error) {
+	for _, element := range expr.Elements {
+		c.TypeOf(element)
+	}
+	return List, nil
+}
+
+func (c *Checker) VisitMapLiteralExpr(expr *expressions.MapLiteral) (interface{}, error) {
+	for index, key := range expr.Keys {
+		c.TypeOf(key)
+		c.TypeOf(expr.Values[index])
+	}
+	return Map, nil
+}
+
+func (c *Checker) VisitBreakExprExpr(expr *expressions.BreakExpr) (interface{}, error) {
+	return Nil, nil
+}
+
+// Patterns bind variables whose type is not known
+
+func (c *Checker) VisitConstantPattern(pattern *expressions.Constant) (interface{}, error) {
+	return nil, nil
+}
+
+func (c *Checker) VisitWildcardPattern(pattern *expressions.Wildcard) (interface{}, error) {
+	return nil, nil
+}
+
+func (c *Checker) VisitBindingPattern(pattern *expressions.Binding) (interface{}, error) {
+	c.Declare(pattern.Name, Any, false)
+	return nil, nil
+}
+
+func (c *Checker) VisitSequencePattern(pattern *expressions.Sequence) (interface{}, error) {
+	for _, element := range pattern.Elements {
+		c.CheckPattern(element)
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitRecordPattern(pattern *expressions.Record) (interface{}, error) {
+	if pattern.Class != nil {
+		c.TypeOf(pattern.Class)
+	}
+	for _, field := range pattern.Patterns {
+		c.CheckPattern(field)
+	}
+	return nil, nil
+}
+
+func (c *Checker) VisitVariantPattern(pattern *expressions.Variant) (interface{}, error) {
+	enum := c.TypeOf(pattern.Enum)
+	if enum.Kind == EnumKind {
+		variant, ok := enum.Variants[pattern.Name.Lexeme]
+		fields := enum.VariantFields[pattern.Name.Lexeme]
+		switch {
+		case !ok:
+			c.Error(pattern.Name, "Enum "+enum.Name+" has no variant "+pattern.Name.Lexeme+".")
+		case pattern.HasPayload && variant.Kind != FunctionKind:
+			c.Error(pattern.Name, "Variant "+pattern.Name.Lexeme+" carries no values.")
+		case pattern.HasPayload && len(pattern.Patterns) != len(fields):
+			c.Error(pattern.Name, fmt.Sprintf("Variant %s carries %d values but the pattern has %d.", pattern.Name.Lexeme, len(fields), len(pattern.Patterns)))
+		}
+	} else if enum.IsKnown() {
+		c.Error(pattern.Name, fmt.Sprintf("Variant pattern requires an enum, not %v.", enum))
+	}
+
+	for _, element := range pattern.Patterns {
+		c.CheckPattern(element)
+	}
+	return nil, nil
+}
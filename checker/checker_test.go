@@ -0,0 +1,107 @@
+package checker_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/lang"
+	"github.com/Atul-Ranjan12/token"
+)
+
+func TestValidPrograms(t *testing.T) {
+	sources := []string{
+		"var x: int = 1; var y: float = x; var z: number = 2.5;",
+		"def add(a: int, b: int): int { return a + b; } var s: int = add(1, 2);",
+		`var s: string = "a" + "b"; var n: int = len(s);`,
+		"var x: int? = nil; x = 3;",
+		"struct P { construct(x) { this.x = x; } } var p: P = P(1);",
+		"var t = time.now() + time.hours(1); var d: duration = t - time.now();",
+		`var r: regex = re.compile("a+"); var found: bool = r.match("aa");`,
+		// A global declared later hides a native of the same name
+		"def f() { return time + 1; } var time = 5; println f();",
+		"def g() { return len + 1; } var len = 1;",
+		"def h() { return math.x; } var math = nil;",
+		// Values without annotations are not checked
+		"def f(a) { return a + 1; } f(\"a\");",
+		"var x = 1; x = \"a\";",
+		"struct P { x: int; construct() { this.x = 1; } } var p = P(); p.x = 2;",
+		"def f(a: string = \"a\", ...rest: int) { } f(); f(\"b\", 1, 2);",
+		"def f(): string? { return nil; } var s: string? = f();",
+		"trait T { f(); } struct S impl T { construct() {} f() {} } var t: T = S();",
+		"var x: number = 1 & 3; var y: int = 1 << 2; var z: float = 2 ** -1;",
+	}
+	for _, source := range sources {
+		if _, err := lang.Compile(source); err != nil {
+			t.Errorf("%s: %v", source, err)
+		}
+	}
+}
+
+func TestTypeErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`var x: int = "a";`, "Can not initialize x of type int with string."},
+		{`println 1 - "a";`, "Operand of '-' must be a number, got string."},
+		{`println "a" + 1;`, "Can not add string and int."},
+		{"def f(a: int) {} f(1, 2);", "Expected"},
+		{"println math.nope;", "Module math has no member nope."},
+		{"println time.now() + 1;", "Operator '+' can not be applied to time and int."},
+		{"println time.now().nope;", "Times have no property nope."},
+		{`println re.compile("a").nope;`, "Regexes have no property nope."},
+		{"println time + 1;", "Operand of '+' must be a number, got module time."},
+		{"def f() { var time = 1; } println time + 1;", "Operand of '+' must be a number, got module time."},
+		{"var x: nope = 1;", "Unknown type nope."},
+		{"var x: int = 1; x = 2.5;", "Can not assign float to x of type int."},
+		{"var x: int = nil;", "Can not initialize x of type int with nil."},
+		{`def f(): int { return "a"; }`, "Can not return string from a function returning int."},
+		{`def f(a: string) {} f(1);`, "Argument 1 of type int does not match parameter type string."},
+		{`def f(a: int = "a") {}`, "Default value of type string does not match parameter type int."},
+		{"var x = 1; x();", "Can not call a value of type int."},
+		{"struct P { x: int; construct() {} } P().x = \"a\";", "Can not assign string to field x of type int."},
+		{"struct P { x: int; x: int; construct() {} }", "Field x is already declared."},
+		{"struct P { construct() {} get a() { return 1; } } P().a = 2;", "Can not set property a which only has a getter."},
+		{"println 1.5 & 1;", "Operands of '&' must be integers."},
+		{"println ~1.5;", "Operand of '~' must be an integer, got float."},
+		{`println -"a";`, "Operand of '-' must be a number, got string."},
+		{`println "a".nope();`, "Strings have no method nope."},
+		{"for (var x in 1) {}", "Can not iterate over a value of type int."},
+		{"println [1][1.5];", "Index must be an integer, got float."},
+		{"println 1[0];", "Values of type int can not be indexed."},
+		{"enum E { A } println E.B;", "Enum E has no variant B."},
+		{"trait D { draw(); } struct P { construct() {} draw() {} } def r(s: D) {} r(P());", "Argument 1 of type P does not match parameter type D."},
+		{"def f(): string { yield 1; }", "Can not yield int from a generator yielding string."},
+	}
+	for _, test := range tests {
+		_, err := lang.Compile(test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestAnnotationsDoNotChangeValues(t *testing.T) {
+	program, err := lang.Compile("var x: float = 1; var y: number = 2.0;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	execution := interpreter.NewExecution(program)
+	if err := program.Execute(execution); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"x", int64(1)},
+		{"y", 2.0},
+	}
+	for _, test := range tests {
+		got, err := execution.Globals.Get(&token.Token{Type: token.IDENTIFIER, Lexeme: test.name})
+		if err != nil || got != test.want {
+			t.Errorf("got %s %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
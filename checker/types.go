@@ -0,0 +1,574 @@
+package checker
+
+import (
+	"math/big"
+
+	"github.com/Atul-Ranjan12/interpreter"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// Kind is the kind of a static type
+type Kind int
+
+const (
+	AnyKind Kind = iota
+	NilKind
+	BoolKind
+	IntKind
+	FloatKind
+	NumberKind
+	StringKind
+	ListKind
+	MapKind
+	FunctionKind
+	StructKind
+	InstanceKind
+	TraitKind
+	EnumKind
+	EnumValueKind
+	GeneratorKind
+	ChannelKind
+	TaskKind
+	PromiseKind
+	ModuleKind
+	TimeKind
+	DurationKind
+	RegexKind
+)
+
+// Type is the static type of an expression. Any is used for
+// everything the checker can not know, a value of type any is
+// never reported
+type Type struct {
+	Kind Kind
+	// Nullable types also allow nil
+	Nullable bool
+	// Name is the name of the struct of struct and instance
+	// types, the name of the trait of trait types and the name
+	// of the enum of enum and enum value types
+	Name string
+	// Traits holds the traits implemented by struct and
+	// instance types
+	Traits []string
+
+	// Function types
+	Params   []*Type
+	MinArity int
+	MaxArity int
+	Return   *Type
+
+	// Struct and trait types
+	Fields        map[string]*Type
+	Methods       map[string]*Type
+	StaticMethods map[string]*Type
+	Constructor   *Type
+	// Getters holds the type a getter returns and Setters
+	// the type a setter takes
+	Getters map[string]*Type
+	Setters map[string]*Type
+
+	// Enum types, a variant is an enum value or a function
+	// creating one. Fields holds the fields of each variant
+	Variants      map[string]*Type
+	VariantFields map[string][]string
+
+	// Generator types, the type of the values yielded
+	Yields *Type
+	// Promise types, the type of the value awaited
+	Resolves *Type
+
+	// Module types, the types of the members
+	Members map[string]*Type
+}
+
+var (
+	Any      = &Type{Kind: AnyKind}
+	Nil      = &Type{Kind: NilKind}
+	Bool     = &Type{Kind: BoolKind}
+	Int      = &Type{Kind: IntKind}
+	Float    = &Type{Kind: FloatKind}
+	Number   = &Type{Kind: NumberKind}
+	String   = &Type{Kind: StringKind}
+	List     = &Type{Kind: ListKind}
+	Map      = &Type{Kind: MapKind}
+	Channel  = &Type{Kind: ChannelKind}
+	Task     = &Type{Kind: TaskKind}
+	Time     = &Type{Kind: TimeKind}
+	Duration = &Type{Kind: DurationKind}
+	Regex    = &Type{Kind: RegexKind}
+)
+
+// typeNames maps the names used in annotations to types
+var typeNames = map[string]*Type{
+	"any":       Any,
+	"nil":       Nil,
+	"bool":      Bool,
+	"int":       Int,
+	"float":     Float,
+	"number":    Number,
+	"string":    String,
+	"list":      List,
+	"map":       Map,
+	"channel":   Channel,
+	"task":      Task,
+	"time":      Time,
+	"duration":  Duration,
+	"regex":     Regex,
+	"function":  {Kind: FunctionKind, MaxArity: interpreter.VARIADIC, Return: Any},
+	"generator": {Kind: GeneratorKind, Yields: Any},
+	"promise":   {Kind: PromiseKind, Resolves: Any},
+}
+
+// NewFunction creates the type of a function
+func NewFunction(params []*Type, minArity, maxArity int, result *Type) *Type {
+	return &Type{
+		Kind:     FunctionKind,
+		Params:   params,
+		MinArity: minArity,
+		MaxArity: maxArity,
+		Return:   result,
+	}
+}
+
+func (t *Type) String() string {
+	var name string
+	switch t.Kind {
+	case AnyKind:
+		return "any"
+	case NilKind:
+		return "nil"
+	case BoolKind:
+		name = "bool"
+	case IntKind:
+		name = "int"
+	case FloatKind:
+		name = "float"
+	case NumberKind:
+		name = "number"
+	case StringKind:
+		name = "string"
+	case ListKind:
+		name = "list"
+	case MapKind:
+		name = "map"
+	case FunctionKind:
+		name = "function"
+	case GeneratorKind:
+		name = "generator"
+	case ChannelKind:
+		name = "channel"
+	case TaskKind:
+		name = "task"
+	case PromiseKind:
+		name = "promise"
+	case ModuleKind:
+		name = "module " + t.Name
+	case TimeKind:
+		name = "time"
+	case DurationKind:
+		name = "duration"
+	case RegexKind:
+		name = "regex"
+	case StructKind:
+		name = "struct " + t.Name
+	case EnumKind:
+		name = "enum " + t.Name
+	case InstanceKind, TraitKind, EnumValueKind:
+		name = t.Name
+	}
+
+	if t.Nullable {
+		return name + "?"
+	}
+	return name
+}
+
+// NewModule creates the type of a module of the standard library
+func NewModule(name string, members map[string]*Type) *Type {
+	return &Type{Kind: ModuleKind, Name: name, Members: members}
+}
+
+// Instance gives the type of the instances of a struct type
+func (t *Type) Instance() *Type {
+	return &Type{Kind: InstanceKind, Name: t.Name, Traits: t.Traits}
+}
+
+// Value gives the type of the values of an enum type
+func (t *Type) Value() *Type {
+	return &Type{Kind: EnumValueKind, Name: t.Name}
+}
+
+// OrNil returns the type that also allows nil
+func (t *Type) OrNil() *Type {
+	if t.Kind == AnyKind || t.Kind == NilKind || t.Nullable {
+		return t
+	}
+	nullable := *t
+	nullable.Nullable = true
+	return &nullable
+}
+
+// NonNil returns the type without nil
+func (t *Type) NonNil() *Type {
+	if !t.Nullable {
+		return t
+	}
+	nonNil := *t
+	nonNil.Nullable = false
+	return &nonNil
+}
+
+// IsNumber checks if values of a type are always numbers
+func (t *Type) IsNumber() bool {
+	return t.Kind == IntKind || t.Kind == FloatKind || t.Kind == NumberKind
+}
+
+// IsKnown checks if the checker knows anything about a type
+func (t *Type) IsKnown() bool {
+	return t.Kind != AnyKind
+}
+
+// IsCallable checks if values of a type can be called
+func (t *Type) IsCallable() bool {
+	return t.Kind == AnyKind || t.Kind == FunctionKind || t.Kind == StructKind
+}
+
+// Assignable checks if a value of type value can be stored
+// where a value of type target is expected
+func Assignable(value, target *Type) bool {
+	if target.Kind == AnyKind || value.Kind == AnyKind {
+		return true
+	}
+	if value.Kind == NilKind {
+		return target.Nullable || target.Kind == NilKind
+	}
+
+	switch target.Kind {
+	case NumberKind:
+		return value.IsNumber()
+	case IntKind:
+		// A number may well be an integer
+		return value.Kind == IntKind || value.Kind == NumberKind
+	case FloatKind:
+		return value.IsNumber()
+	case FunctionKind:
+		return value.Kind == FunctionKind || value.Kind == StructKind
+	case InstanceKind, StructKind, EnumKind, EnumValueKind:
+		return value.Kind == target.Kind && value.Name == target.Name
+	case TraitKind:
+		if value.Kind == InstanceKind {
+			for _, trait := range value.Traits {
+				if trait == target.Name {
+					return true
+				}
+			}
+		}
+		return value.Kind == TraitKind && value.Name == target.Name
+	}
+	return value.Kind == target.Kind
+}
+
+// Join gives a type for a value that is either of two types
+func Join(a, b *Type) *Type {
+	switch {
+	case a.Kind == AnyKind || b.Kind == AnyKind:
+		return Any
+	case a.Kind == NilKind && b.Kind == NilKind:
+		return Nil
+	case a.Kind == NilKind:
+		return b.OrNil()
+	case b.Kind == NilKind:
+		return a.OrNil()
+	}
+
+	var joined *Type
+	switch {
+	case a.Kind == b.Kind && a.Name == b.Name && a.Kind != FunctionKind:
+		joined = a
+	case a.IsNumber() && b.IsNumber():
+		joined = Number
+	case a.Kind == FunctionKind && b.Kind == FunctionKind:
+		joined = typeNames["function"]
+	default:
+		return Any
+	}
+
+	if a.Nullable || b.Nullable {
+		return joined.OrNil()
+	}
+	return joined.NonNil()
+}
+
+// literalType gives the type of a literal value
+func literalType(value interface{}) *Type {
+	switch value.(type) {
+	case nil:
+		return Nil
+	case bool:
+		return Bool
+	case int64, *big.Int:
+		return Int
+	case float64:
+		return Float
+	case string:
+		return String
+	}
+	return Any
+}
+
+// arithmeticType gives the type of the result of an
+// arithmetic operator on two numbers
+func arithmeticType(operator token.TokenType, left, right *Type) *Type {
+	switch operator {
+	case token.SLASH:
+		return Float
+	case token.STAR_STAR:
+		// A negative exponent gives a float
+		if left.Kind == FloatKind || right.Kind == FloatKind {
+			return Float
+		}
+		return Number
+	}
+
+	switch {
+	case left.Kind == FloatKind || right.Kind == FloatKind:
+		return Float
+	case left.Kind == IntKind && right.Kind == IntKind:
+		return Int
+	}
+	return Number
+}
+
+// timeArithmeticType gives the type of the result of an
+// operator on times and durations, ok is false when the
+// operator can not be applied to them
+func timeArithmeticType(operator token.TokenType, left, right *Type) (result *Type, ok bool) {
+	switch operator {
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		return Bool, left.Kind == right.Kind
+	}
+
+	switch {
+	case left.Kind == TimeKind && right.Kind == DurationKind:
+		return Time, operator == token.PLUS || operator == token.MINUS
+	case left.Kind == TimeKind && right.Kind == TimeKind:
+		return Duration, operator == token.MINUS
+	case left.Kind == DurationKind && right.Kind == DurationKind:
+		if operator == token.SLASH {
+			return Float, true
+		}
+		return Duration, operator == token.PLUS || operator == token.MINUS
+	case left.Kind == DurationKind && right.IsNumber():
+		return Duration, operator == token.STAR || operator == token.SLASH
+	case left.IsNumber() && right.Kind == DurationKind:
+		return Duration, operator == token.STAR
+	}
+	return nil, false
+}
+
+// stringMethodTypes are the types of the methods on strings
+var stringMethodTypes = map[string]*Type{
+	"upper":      NewFunction(nil, 0, 0, String),
+	"lower":      NewFunction(nil, 0, 0, String),
+	"trim":       NewFunction(nil, 0, 0, String),
+	"split":      NewFunction([]*Type{String}, 1, 1, List),
+	"contains":   NewFunction([]*Type{String}, 1, 1, Bool),
+	"replace":    NewFunction([]*Type{String, String}, 2, 2, String),
+	"startsWith": NewFunction([]*Type{String}, 1, 1, Bool),
+	"endsWith":   NewFunction([]*Type{String}, 1, 1, Bool),
+	"indexOf":    NewFunction([]*Type{String}, 1, 1, Int),
+	"repeat":     NewFunction([]*Type{Int}, 1, 1, String),
+	"format":     NewFunction(nil, 0, interpreter.VARIADIC, String),
+}
+
+// nativeTypes are the types of the native functions
+var nativeTypes = map[string]*Type{
+	"clock":      NewFunction(nil, 0, 0, Float),
+	"len":        NewFunction([]*Type{Any}, 1, 1, Int),
+	"charAt":     NewFunction([]*Type{String, Int}, 2, 2, String),
+	"slice":      NewFunction([]*Type{String, Int, Int.OrNil()}, 2, 3, String),
+	"freeze":     NewFunction([]*Type{Any}, 1, 1, Any),
+	"isFrozen":   NewFunction([]*Type{Any}, 1, 1, Bool),
+	"implements": NewFunction([]*Type{Any, Any}, 2, 2, Bool),
+	"channel":    NewFunction([]*Type{Int}, 0, 1, Channel),
+	// Timers and non-blocking I/O
+	"sleep":          NewFunction([]*Type{Number}, 1, 1, &Type{Kind: PromiseKind, Resolves: Nil}),
+	"setTimeout":     NewFunction([]*Type{typeNames["function"], Number}, 2, 2, &Type{Kind: PromiseKind, Resolves: Any}),
+	"readFileAsync":  NewFunction([]*Type{String}, 1, 1, &Type{Kind: PromiseKind, Resolves: String}),
+	"writeFileAsync": NewFunction([]*Type{String, String}, 2, 2, &Type{Kind: PromiseKind, Resolves: Nil}),
+	"connect":        NewFunction([]*Type{String}, 1, 1, &Type{Kind: PromiseKind, Resolves: Any}),
+	// Arguments, environment variables and processes
+	"args":   NewFunction(nil, 0, 0, List),
+	"env":    NewFunction([]*Type{String}, 1, 1, String.OrNil()),
+	"setEnv": NewFunction([]*Type{String, String}, 2, 2, Nil),
+	"exit":   NewFunction([]*Type{Int}, 0, 1, Nil),
+	"exec":   NewFunction([]*Type{String, List}, 1, 2, Map),
+	// Modules
+	"math": mathModuleType,
+	"fs":   fsModuleType,
+	"path": pathModuleType,
+	"json": jsonModuleType,
+	"http": httpModuleType,
+	"time": timeModuleType,
+	"re":   reModuleType,
+}
+
+// mathFunction is the type of the math functions of one number
+// giving a float
+var mathFunction = NewFunction([]*Type{Number}, 1, 1, Float)
+
+// mathModuleType is the type of the math module
+var mathModuleType = NewModule("math", map[string]*Type{
+	"PI":      Float,
+	"E":       Float,
+	"INF":     Float,
+	"NaN":     Float,
+	"sqrt":    mathFunction,
+	"exp":     mathFunction,
+	"sin":     mathFunction,
+	"cos":     mathFunction,
+	"tan":     mathFunction,
+	"asin":    mathFunction,
+	"acos":    mathFunction,
+	"atan":    mathFunction,
+	"atan2":   NewFunction([]*Type{Number, Number}, 2, 2, Float),
+	"pow":     NewFunction([]*Type{Number, Number}, 2, 2, Float),
+	"log":     NewFunction([]*Type{Number, Number}, 1, 2, Float),
+	"abs":     NewFunction([]*Type{Number}, 1, 1, Number),
+	"floor":   NewFunction([]*Type{Number}, 1, 1, Int),
+	"ceil":    NewFunction([]*Type{Number}, 1, 1, Int),
+	"round":   NewFunction([]*Type{Number, Int}, 1, 2, Number),
+	"min":     NewFunction(nil, 1, interpreter.VARIADIC, Number),
+	"max":     NewFunction(nil, 1, interpreter.VARIADIC, Number),
+	"seed":    NewFunction([]*Type{Int}, 1, 1, Nil),
+	"random":  NewFunction(nil, 0, 0, Float),
+	"randint": NewFunction([]*Type{Int, Int}, 2, 2, Int),
+	"choice":  NewFunction([]*Type{List}, 1, 1, Any),
+	"shuffle": NewFunction([]*Type{List}, 1, 1, Nil),
+})
+
+// channelMethodTypes are the types of the methods on channels
+var channelMethodTypes = map[string]*Type{
+	"send":  NewFunction([]*Type{Any}, 1, 1, Nil),
+	"recv":  NewFunction(nil, 0, 0, Any),
+	"close": NewFunction(nil, 0, 0, Nil),
+}
+
+// taskMethodTypes are the types of the methods on tasks
+var taskMethodTypes = map[string]*Type{
+	"join": NewFunction(nil, 0, 0, Any),
+	"done": NewFunction(nil, 0, 0, Bool),
+}
+
+// fsModuleType is the type of the fs module
+var fsModuleType = NewModule("fs", map[string]*Type{
+	"readFile":   NewFunction([]*Type{String}, 1, 1, String),
+	"writeFile":  NewFunction([]*Type{String, String}, 2, 2, Nil),
+	"appendFile": NewFunction([]*Type{String, String}, 2, 2, Nil),
+	"exists":     NewFunction([]*Type{String}, 1, 1, Bool),
+	"listDir":    NewFunction([]*Type{String}, 1, 1, List),
+	"mkdir":      NewFunction([]*Type{String}, 1, 1, Nil),
+	"remove":     NewFunction([]*Type{String}, 1, 1, Nil),
+	"stat":       NewFunction([]*Type{String}, 1, 1, Map),
+	"glob":       NewFunction([]*Type{String}, 1, 1, List),
+})
+
+// pathModuleType is the type of the path module
+var pathModuleType = NewModule("path", map[string]*Type{
+	"join": NewFunction(nil, 1, interpreter.VARIADIC, String),
+	"base": NewFunction([]*Type{String}, 1, 1, String),
+	"dir":  NewFunction([]*Type{String}, 1, 1, String),
+	"ext":  NewFunction([]*Type{String}, 1, 1, String),
+})
+
+// jsonModuleType is the type of the json module
+var jsonModuleType = NewModule("json", map[string]*Type{
+	"parse":     NewFunction([]*Type{String}, 1, 1, Any),
+	"stringify": NewFunction([]*Type{Any, Any}, 1, 2, String),
+})
+
+// httpResponse is the type of the promise of a response of the
+// http client
+var httpResponse = &Type{Kind: PromiseKind, Resolves: Map}
+
+// httpModuleType is the type of the http module
+var httpModuleType = NewModule("http", map[string]*Type{
+	"get":     NewFunction([]*Type{String, Map}, 1, 2, httpResponse),
+	"post":    NewFunction([]*Type{String, Any, Map}, 2, 3, httpResponse),
+	"request": NewFunction([]*Type{Map}, 1, 1, httpResponse),
+	"serve":   NewFunction([]*Type{String, typeNames["function"]}, 2, 2, Any),
+})
+
+// durationFunction is the type of the functions giving the
+// duration of a number of units
+var durationFunction = NewFunction([]*Type{Number}, 1, 1, Duration)
+
+// timeModuleType is the type of the time module
+var timeModuleType = NewModule("time", map[string]*Type{
+	"RFC3339":      String,
+	"DATE":         String,
+	"TIME":         String,
+	"DATETIME":     String,
+	"now":          NewFunction(nil, 0, 0, Time),
+	"date":         NewFunction([]*Type{Int, Int, Int, Int, Int, Int, String}, 3, 7, Time),
+	"unix":         NewFunction([]*Type{Number}, 1, 1, Time),
+	"parse":        NewFunction([]*Type{String, String, String}, 2, 3, Time),
+	"since":        NewFunction([]*Type{Time}, 1, 1, Duration),
+	"duration":     NewFunction([]*Type{String}, 1, 1, Duration),
+	"milliseconds": durationFunction,
+	"seconds":      durationFunction,
+	"minutes":      durationFunction,
+	"hours":        durationFunction,
+	"sleep":        NewFunction([]*Type{Any}, 1, 1, &Type{Kind: PromiseKind, Resolves: Nil}),
+})
+
+// timePropertyTypes are the types of the properties and
+// methods of times
+var timePropertyTypes = map[string]*Type{
+	"year":       Int,
+	"month":      Int,
+	"day":        Int,
+	"hour":       Int,
+	"minute":     Int,
+	"second":     Int,
+	"nanosecond": Int,
+	"weekday":    String,
+	"yearDay":    Int,
+	"unix":       Int,
+	"unixMillis": Int,
+	"zone":       String,
+	"format":     NewFunction([]*Type{String}, 1, 1, String),
+	"add":        NewFunction([]*Type{Duration}, 1, 1, Time),
+	"addDate":    NewFunction([]*Type{Int, Int, Int}, 3, 3, Time),
+	"sub":        NewFunction([]*Type{Time}, 1, 1, Duration),
+	"inZone":     NewFunction([]*Type{String}, 1, 1, Time),
+	"utc":        NewFunction(nil, 0, 0, Time),
+}
+
+// durationPropertyTypes are the types of the properties of
+// durations
+var durationPropertyTypes = map[string]*Type{
+	"hours":        Float,
+	"minutes":      Float,
+	"seconds":      Float,
+	"milliseconds": Int,
+	"nanoseconds":  Int,
+}
+
+// reModuleType is the type of the re module
+var reModuleType = NewModule("re", map[string]*Type{
+	"compile": NewFunction([]*Type{String}, 1, 1, Regex),
+	"escape":  NewFunction([]*Type{String}, 1, 1, String),
+})
+
+// regexPropertyTypes are the types of the pattern and the
+// methods of regexes, a match is a map
+var regexPropertyTypes = map[string]*Type{
+	"pattern": String,
+	"match":   NewFunction([]*Type{String}, 1, 1, Bool),
+	"find":    NewFunction([]*Type{String}, 1, 1, Map.OrNil()),
+	"findAll": NewFunction([]*Type{String, Int.OrNil()}, 1, 2, List),
+	"replace": NewFunction([]*Type{String, Any}, 2, 2, String),
+	"split":   NewFunction([]*Type{String, Int.OrNil()}, 1, 2, List),
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"os"
 	"strings"
 
+	"github.com/Atul-Ranjan12/interpreter"
 	"github.com/Atul-Ranjan12/lang"
 	"github.com/Atul-Ranjan12/parser/astprinter"
 	"github.com/Atul-Ranjan12/parser/expressions"
@@ -52,62 +55,52 @@ func PrintAST(stmt expressions.Stmt, depth int) {
 	}
 }
 
-// Interperter Main
-
-// func main() {
-// 	// os.Args[0] is the program name
-// 	// everything after that contains the arguments
-// 	// Has more than the file to interpret
-// 	if len(os.Args) > 2 {
-// 		log.Fatal("Unexpected arguments")
-// 		return
-// 	} else if len(os.Args) == 2 {
-// 		// Here we run the file
-// 		tools.RunFile(os.Args[1])
-// 	} else {
-// 		// Here we run the prompt
-// 		tools.RunPrompt()
-// 	}
-// }
-
+// main runs a program, the arguments after its path are passed
+// to the program. The status is the one given to exit, 65 for
+// an invalid program and 70 for a runtime error
 func main() {
-	// Read the contents of test.rnj
-	content, err := ioutil.ReadFile("test.lang")
+	printAST := flag.Bool("ast", false, "print the AST before running the program")
+	flag.Parse()
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: lang [-ast] program.lang [arguments...]")
+		os.Exit(64)
+	}
+
+	content, err := os.ReadFile(flag.Arg(0))
 	if err != nil {
 		log.Fatalf("Error reading file: %v", err)
 	}
 
-	// Create a new lexer with the file contents
-	lang := lang.NewLang(string(content))
-
-	// Parse
-	statements, err := lang.Parser.Parse()
+	// Parse, resolve and type check before anything runs
+	source := lang.NewLang(string(content))
+	program, err := source.Compile()
 	if err != nil {
-		log.Println("An error occured while parsing: ", err)
-		return
+		// Errors of the lexer are reported as they are found
+		if !source.HasError() {
+			log.Println(err)
+		}
+		os.Exit(65)
 	}
-
-	fmt.Println("AST Structure:")
-	for _, statement := range statements {
-		PrintAST(statement, 0)
+	for _, warning := range program.Warnings {
+		log.Println(warning)
 	}
 
-	// log.Println("Printing statements: ", statements)
-	// log.Println("Successful parse ")
-	// log.Println("\nOutput")
-
-	// Resolve it here
-	err = lang.Resolver.ResolveStatements(statements)
-	if err != nil {
-		log.Println("Error in resolving the code: ", err)
-		return
+	if *printAST {
+		fmt.Println("AST Structure:")
+		for _, statement := range program.Statements() {
+			PrintAST(statement, 0)
+		}
 	}
 
-	err = lang.Interpreter.Interpret(statements)
+	execution := interpreter.NewExecution(program)
+	execution.Host.Args = flag.Args()[1:]
+	err = program.Execute(execution)
+	var exit *interpreter.ExitSignal
+	if errors.As(err, &exit) {
+		os.Exit(exit.Code)
+	}
 	if err != nil {
 		log.Println("Interpretation Error: ", err)
+		os.Exit(70)
 	}
-
-	log.Println("\n\nProgram execution successful")
-
 }
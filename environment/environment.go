@@ -2,17 +2,20 @@ package environment
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/Atul-Ranjan12/token"
 )
 
 // Environment keeps track of all the states
-// and values of variables
+// and values of variables. Environments are shared
+// between tasks so the values are guarded by a mutex
 type Environment struct {
 	Enclosing *Environment
 	// Values stores all the values in the
 	// interpreter
 	Values map[string]interface{}
+	mutex  sync.RWMutex
 }
 
 // NewEnvironment creates a new environment for the
@@ -26,15 +29,20 @@ func NewEnvironment(enclosing *Environment) *Environment {
 
 // Define defines a value in the map
 func (e *Environment) Define(name string, value interface{}) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
 	e.Values[name] = value
 }
 
 // Assigns a new value to the map
 func (e *Environment) Assign(name token.Token, value interface{}) error {
+	e.mutex.Lock()
 	if _, exists := e.Values[name.Lexeme]; exists {
 		e.Values[name.Lexeme] = value
+		e.mutex.Unlock()
 		return nil
 	}
+	e.mutex.Unlock()
 
 	if e.Enclosing != nil {
 		return e.Enclosing.Assign(name, value)
@@ -46,7 +54,9 @@ func (e *Environment) Assign(name token.Token, value interface{}) error {
 func (e *Environment) Get(name *token.Token) (interface{}, error) {
 	// Firstly check if the value of the variable is in that
 	// current environment
+	e.mutex.RLock()
 	value, exists := e.Values[name.Lexeme]
+	e.mutex.RUnlock()
 	if exists {
 		return value, nil
 	}
@@ -63,14 +73,20 @@ func (e *Environment) Get(name *token.Token) (interface{}, error) {
 // GetAt gets the value at a distance
 func (e *Environment) GetAt(distance int, name string) interface{} {
 	// log.Println("This is e.Ancestor: ", e.Ancestor(distance).Values)
-	val := e.Ancestor(distance).Values[name]
+	ancestor := e.Ancestor(distance)
+	ancestor.mutex.RLock()
+	defer ancestor.mutex.RUnlock()
+	val := ancestor.Values[name]
 	// log.Println("This is what we got for this: ", val)
 	return val
 }
 
 // AssignAt assigns the value at a distance
 func (e *Environment) AssignAt(distance int, name *token.Token, value interface{}) {
-	e.Ancestor(distance).Values[name.Lexeme] = value
+	ancestor := e.Ancestor(distance)
+	ancestor.mutex.Lock()
+	defer ancestor.mutex.Unlock()
+	ancestor.Values[name.Lexeme] = value
 }
 
 // Ancestor gets the environment at a distance
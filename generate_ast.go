@@ -75,15 +75,23 @@ func main() {
 	err := defineAst(outputDir, "Expr", []AstType{
 		{"Assign", []string{"Name token.Token", "Value Expr"}},
 		{"Logical", []string{"Left Expr", "Right Expr", "Operator token.Token"}},
+		{"Conditional", []string{"Condition Expr", "ThenBranch Expr", "ElseBranch Expr"}},
 		{"Binary", []string{"Left Expr", "Operator token.Token", "Right Expr"}},
-		{"Call", []string{"Callee Expr", "Paren token.Token", "Arguments []Expr"}},
-		{"Get", []string{"Object Expr", "Name token.Token"}},
+		{"Call", []string{"Callee Expr", "Paren token.Token", "Arguments []Expr", "Keywords []token.Token", "KeywordValues []Expr"}},
+		{"Spread", []string{"Ellipsis token.Token", "Expression Expr"}},
+		{"Spawn", []string{"Keyword token.Token", "Call *Call"}},
+		{"Await", []string{"Keyword token.Token", "Value Expr"}},
+		{"Get", []string{"Object Expr", "Name token.Token", "Optional bool"}},
 		{"Set", []string{"Object Expr", "Name token.Token", "Value Expr"}},
+		{"Index", []string{"Object Expr", "Bracket token.Token", "Index Expr"}},
+		{"SetIndex", []string{"Object Expr", "Bracket token.Token", "Index Expr", "Value Expr"}},
 		{"This", []string{"Keyword token.Token"}},
 		{"Grouping", []string{"Expression Expr"}},
 		{"Literal", []string{"Value interface{}"}},
 		{"Unary", []string{"Operator token.Token", "Right Expr"}},
 		{"Variable", []string{"Name token.Token"}},
+		{"ListLiteral", []string{"Bracket token.Token", "Elements []Expr"}},
+		{"MapLiteral", []string{"Brace token.Token", "Keys []Expr", "Values []Expr"}},
 		{"BreakExpr", []string{}},
 	})
 	if err != nil {
@@ -91,18 +99,37 @@ func main() {
 	}
 	err = defineAst(outputDir, "Stmt", []AstType{
 		{"Block", []string{"Statements []Stmt"}},
-		{"Class", []string{"Name token.Token", "Methods []*Function"}},
+		{"Class", []string{"Name token.Token", "Traits []*Variable", "Fields []token.Token", "FieldTypes []*TypeAnnotation", "Methods []*Function", "StaticMethods []*Function", "Getters []*Function", "Setters []*Function"}},
+		{"Trait", []string{"Name token.Token", "Methods []*Function"}},
+		{"Enum", []string{"Name token.Token", "Variants []token.Token", "Fields [][]token.Token"}},
 		{"ExprStatement", []string{"Expression Expr"}},
 		{"PrintStatement", []string{"Expression Expr"}},
 		{"Return", []string{"Keyword token.Token", "Value Expr"}},
 		{"WhileStatement", []string{"Condition Expr", "Body Stmt"}},
-		{"Var", []string{"Name token.Token", "Initializer Expr"}},
+		{"ForIn", []string{"Keyword token.Token", "Name token.Token", "Iterable Expr", "Body Stmt"}},
+		{"Yield", []string{"Keyword token.Token", "Value Expr"}},
+		{"Var", []string{"Name token.Token", "Type *TypeAnnotation", "Initializer Expr", "Constant bool"}},
+		{"Destructure", []string{"Keyword token.Token", "Pattern Pattern", "Initializer Expr", "Constant bool"}},
+		{"MultiAssign", []string{"Targets []Expr", "Equals token.Token", "Values []Expr"}},
 		{"If", []string{"Condition Expr", "ThenBranch Stmt", "ElseBranch Stmt"}},
-		{"Function", []string{"Name token.Token", "Params []token.Token", "Body []Stmt"}},
+		{"Function", []string{"Name token.Token", "Params []token.Token", "ParamTypes []*TypeAnnotation", "Defaults []Expr", "Variadic bool", "ReturnType *TypeAnnotation", "Body []Stmt", "Generator bool", "Async bool"}},
+		{"Match", []string{"Keyword token.Token", "Value Expr", "Arms []*MatchArm"}},
+		{"Select", []string{"Keyword token.Token", "Cases []*SelectCase", "Default Stmt"}},
 	})
 	if err != nil {
 		log.Fatalf("Error generating Expr AST: %v", err)
 	}
+	err = defineAst(outputDir, "Pattern", []AstType{
+		{"Constant", []string{"Token token.Token", "Value interface{}"}},
+		{"Wildcard", []string{"Token token.Token"}},
+		{"Binding", []string{"Name token.Token"}},
+		{"Sequence", []string{"Bracket token.Token", "Elements []Pattern"}},
+		{"Record", []string{"Brace token.Token", "Class *Variable", "Fields []token.Token", "Patterns []Pattern"}},
+		{"Variant", []string{"Enum *Variable", "Name token.Token", "HasPayload bool", "Patterns []Pattern"}},
+	})
+	if err != nil {
+		log.Fatalf("Error generating Pattern AST: %v", err)
+	}
 
 	log.Println("Successfully generated ASTs at:", outputDir)
 }
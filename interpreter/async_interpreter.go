@@ -0,0 +1,516 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Atul-Ranjan12/environment"
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles async functions, the promises they return
+// and the event loop running them together with timers and I/O.
+// The loop runs one async function at a time, await at the top
+// level runs it until the promise settles and it runs again
+// after the last statement. Rejected promises that were never
+// awaited are reported with the calls that created them
+
+// EventLoop runs jobs one at a time in the order they are
+// scheduled. A job starts or resumes an async function, calls
+// a timer callback or settles the promise of finished I/O
+type EventLoop struct {
+	mutex sync.Mutex
+	wake  *sync.Cond
+	jobs  []func()
+	// pending counts the timers and I/O that will schedule
+	// a job once they are done
+	pending int
+	// rejected holds the rejected promises, the ones never
+	// awaited are reported when the program exits
+	rejected []*Promise
+	// exit is set once a job calls exit, no jobs run after it
+	exit *ExitSignal
+	// stopped is closed once exit is set so tasks waiting on
+	// channels stop waiting
+	stopped chan struct{}
+}
+
+// NewEventLoop creates an event loop without jobs
+func NewEventLoop() *EventLoop {
+	l := &EventLoop{stopped: make(chan struct{})}
+	l.wake = sync.NewCond(&l.mutex)
+	return l
+}
+
+// Schedule adds a job to run after the jobs already scheduled
+func (l *EventLoop) Schedule(job func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.jobs = append(l.jobs, job)
+	l.wake.Broadcast()
+}
+
+// Begin counts a timer or I/O operation, Finish must be
+// called once it is done
+func (l *EventLoop) Begin() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.pending++
+}
+
+// Finish schedules the job of a finished timer or I/O operation
+func (l *EventLoop) Finish(job func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.pending--
+	l.jobs = append(l.jobs, job)
+	l.wake.Broadcast()
+}
+
+// next waits for the next job, ok is false when there are no
+// jobs left and nothing pending that could schedule one
+func (l *EventLoop) next() (job func(), ok bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for len(l.jobs) == 0 && l.pending > 0 && l.exit == nil {
+		l.wake.Wait()
+	}
+	if len(l.jobs) == 0 || l.exit != nil {
+		return nil, false
+	}
+	job = l.jobs[0]
+	l.jobs = l.jobs[1:]
+	return job, true
+}
+
+// RunUntil runs jobs until done reports true, false is returned
+// when there is nothing left to run before that
+func (l *EventLoop) RunUntil(done func() bool) bool {
+	for !done() {
+		job, ok := l.next()
+		if !ok {
+			return false
+		}
+		job()
+	}
+	return true
+}
+
+// Run runs jobs until there is nothing left to run
+func (l *EventLoop) Run() {
+	l.RunUntil(func() bool { return false })
+}
+
+// Exit stops the event loop when a job calls exit
+func (l *EventLoop) Exit(signal *ExitSignal) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.exit == nil {
+		l.exit = signal
+		close(l.stopped)
+	}
+	l.jobs = nil
+	l.wake.Broadcast()
+}
+
+// Exited returns the exit signal of a job that called exit
+func (l *EventLoop) Exited() *ExitSignal {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.exit
+}
+
+// Stopped is closed once a job or a task calls exit
+func (l *EventLoop) Stopped() <-chan struct{} {
+	return l.stopped
+}
+
+// settle settles a promise with the result of a job, a job that
+// called exit stops the event loop instead
+func (l *EventLoop) settle(p *Promise, value interface{}, err error) {
+	if signal, ok := err.(*ExitSignal); ok {
+		l.Exit(signal)
+		return
+	}
+	p.Settle(l, value, err)
+}
+
+// reject records a rejected promise
+func (l *EventLoop) reject(p *Promise) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.rejected = append(l.rejected, p)
+}
+
+// Unhandled returns an error describing the rejected promises
+// that were never awaited, nil when there are none. Each
+// rejection is only reported once
+func (l *EventLoop) Unhandled() error {
+	l.mutex.Lock()
+	rejected := l.rejected
+	l.rejected = nil
+	l.mutex.Unlock()
+
+	var reports []string
+	for _, p := range rejected {
+		p.mutex.Lock()
+		if !p.handled {
+			reports = append(reports, fmt.Sprintf("Unhandled rejection of %s: %v%s", p.Name, p.err, p.Trace))
+		}
+		p.mutex.Unlock()
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(reports, "\n"))
+}
+
+// Promise is the result of an async function, a timer or I/O
+// which is known once it settles. A promise settled with an
+// error is rejected
+type Promise struct {
+	Name string
+	// Trace describes where the promise was created
+	Trace string
+
+	mutex   sync.Mutex
+	settled bool
+	value   interface{}
+	err     error
+	// handled is set once the result has been awaited
+	handled   bool
+	callbacks []func()
+}
+
+// NewPromise creates a promise that is not settled
+func NewPromise(name, trace string) *Promise {
+	return &Promise{Name: name, Trace: trace}
+}
+
+func (p *Promise) String() string {
+	return "<promise " + p.Name + ">"
+}
+
+// Settle fulfills the promise with a value or rejects it with
+// an error, the callbacks waiting for it are scheduled
+func (p *Promise) Settle(l *EventLoop, value interface{}, err error) {
+	p.mutex.Lock()
+	if p.settled {
+		p.mutex.Unlock()
+		return
+	}
+	p.settled, p.value, p.err = true, value, err
+	callbacks := p.callbacks
+	p.callbacks = nil
+	p.mutex.Unlock()
+
+	if err != nil {
+		l.reject(p)
+	}
+	for _, callback := range callbacks {
+		l.Schedule(callback)
+	}
+}
+
+// OnSettle schedules a callback once the promise settles
+func (p *Promise) OnSettle(l *EventLoop, callback func()) {
+	p.mutex.Lock()
+	if !p.settled {
+		p.callbacks = append(p.callbacks, callback)
+		p.mutex.Unlock()
+		return
+	}
+	p.mutex.Unlock()
+	l.Schedule(callback)
+}
+
+// Settled checks if the promise has settled
+func (p *Promise) Settled() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.settled
+}
+
+// Result returns the value or the error of a settled promise
+// and marks its rejection as handled
+func (p *Promise) Result() (interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.handled = true
+	return p.value, p.err
+}
+
+// asyncContext is the body of an async function running on its
+// own goroutine, it only runs while the event loop waits for it
+type asyncContext struct {
+	body    func()
+	started bool
+	resume  chan struct{}
+	parked  chan struct{}
+}
+
+// step runs the body until it awaits or finishes
+func (c *asyncContext) step() {
+	if !c.started {
+		c.started = true
+		go c.body()
+	} else {
+		c.resume <- struct{}{}
+	}
+	<-c.parked
+}
+
+// Trace describes the calls being made, innermost first
+func (i *Interpreter) Trace() string {
+	var builder strings.Builder
+	for index := len(i.Frames) - 1; index >= 0; index-- {
+		fmt.Fprintf(&builder, "\n    in %s called at line %d", i.Frames[index].Name, i.Frames[index].Line)
+	}
+	return builder.String()
+}
+
+// StartAsync schedules the body of an async function on the
+// event loop and returns the promise of its result
+func (i *Interpreter) StartAsync(f *Function, env *environment.Environment) *Promise {
+	promise := NewPromise(f.Declaration.Name.Lexeme, i.Trace())
+	fork := i.Fork()
+	context := &asyncContext{
+		resume: make(chan struct{}),
+		parked: make(chan struct{}),
+	}
+	fork.Context = context
+	context.body = func() {
+		value, err := f.Run(fork, env)
+		i.Loop.settle(promise, value, err)
+		context.parked <- struct{}{}
+	}
+
+	i.Loop.Schedule(context.step)
+	return promise
+}
+
+// VisitAwaitExpr waits for a promise to settle and gives its
+// value, a rejected promise raises its error. An async function
+// is parked while it waits, at the top level the event loop
+// runs until the promise settles. Any other value is returned
+func (i *Interpreter) VisitAwaitExpr(expr *expressions.Await) (interface{}, error) {
+	value, err := i.Evaluate(expr.Value)
+	if err != nil {
+		return nil, err
+	}
+	promise, ok := value.(*Promise)
+	if !ok {
+		return value, nil
+	}
+
+	if !promise.Settled() {
+		if context := i.Context; context != nil {
+			promise.OnSettle(i.Loop, context.step)
+			context.parked <- struct{}{}
+			<-context.resume
+		} else if !i.Loop.RunUntil(promise.Settled) {
+			if exit := i.Loop.Exited(); exit != nil {
+				return nil, exit
+			}
+			return nil, i.RuntimeError(expr.Keyword, "Promise of "+promise.Name+" can never settle")
+		}
+	}
+	return promise.Result()
+}
+
+// Background runs blocking work on its own goroutine and
+// returns a promise settled by the event loop once it is done
+func (i *Interpreter) Background(name string, work func() (interface{}, error)) *Promise {
+	promise := NewPromise(name, i.Trace())
+	i.Loop.Begin()
+	go func() {
+		value, err := work()
+		i.Loop.Finish(func() { promise.Settle(i.Loop, value, err) })
+	}()
+	return promise
+}
+
+// DefineAsyncFunctions defines the natives for timers and
+// non-blocking I/O, each returns a promise
+func (i *Interpreter) DefineAsyncFunctions() {
+	i.Define(i.Globals, NewNativeFunction("sleep", 1, sleep), "sleep")
+	i.Define(i.Globals, NewNativeFunction("setTimeout", 2, setTimeout), "setTimeout")
+	i.Define(i.Globals, NewNativeFunction("readFileAsync", 1, readFileAsync), "readFileAsync")
+	i.Define(i.Globals, NewNativeFunction("writeFileAsync", 2, writeFileAsync), "writeFileAsync")
+	i.Define(i.Globals, NewNativeFunction("connect", 1, connect), "connect")
+}
+
+// durationArg converts a number of milliseconds to a duration
+func durationArg(i *Interpreter, name string, arg interface{}) (time.Duration, error) {
+	ms := toFloat(arg)
+	duration, ok := durationOf(ms * float64(time.Millisecond))
+	if !ok || ms < 0 {
+		return 0, errors.New(name + " expects a non negative number of milliseconds of at most 292 years but got " + i.stringify(arg))
+	}
+	return duration, nil
+}
+
+// sleep gives a promise fulfilled with nil after a number of
+// milliseconds
+func sleep(i *Interpreter, args []interface{}) (interface{}, error) {
+	duration, err := durationArg(i, "sleep", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	promise := NewPromise("sleep", i.Trace())
+	i.Loop.Begin()
+	time.AfterFunc(duration, func() {
+		i.Loop.Finish(func() { promise.Settle(i.Loop, nil, nil) })
+	})
+	return promise, nil
+}
+
+// setTimeout calls a function without arguments on the event
+// loop after a number of milliseconds and gives a promise of its
+// result, an error in the callback that is never awaited is
+// reported like an unhandled rejection
+func setTimeout(i *Interpreter, args []interface{}) (interface{}, error) {
+	callback, ok := args[0].(Callable)
+	if !ok {
+		return nil, errors.New("setTimeout expects a function but got " + i.stringify(args[0]))
+	}
+	if callback.MinArity() > 0 {
+		return nil, errors.New("setTimeout expects a function without parameters")
+	}
+	duration, err := durationArg(i, "setTimeout", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	promise := NewPromise("setTimeout callback", i.Trace())
+	fork := i.Fork()
+	i.Loop.Begin()
+	time.AfterFunc(duration, func() {
+		i.Loop.Finish(func() {
+			value, err := callback.Call(fork, nil)
+			i.Loop.settle(promise, value, err)
+		})
+	})
+	return promise, nil
+}
+
+// readFileAsync gives a promise of the contents of a file
+func readFileAsync(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckFileSystem("readFileAsync"); err != nil {
+		return nil, err
+	}
+	path, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("readFileAsync expects a path that is a string but got " + i.stringify(args[0]))
+	}
+	return i.Background("readFileAsync", func() (interface{}, error) {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return string(contents), nil
+	}), nil
+}
+
+// writeFileAsync gives a promise fulfilled with nil once a
+// string has been written to a file
+func writeFileAsync(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckFileSystem("writeFileAsync"); err != nil {
+		return nil, err
+	}
+	path, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("writeFileAsync expects a path that is a string but got " + i.stringify(args[0]))
+	}
+	contents, ok := args[1].(string)
+	if !ok {
+		return nil, errors.New("writeFileAsync expects contents that are a string but got " + i.stringify(args[1]))
+	}
+	return i.Background("writeFileAsync", func() (interface{}, error) {
+		return nil, os.WriteFile(path, []byte(contents), 0644)
+	}), nil
+}
+
+// CheckNetwork returns an error when the host has disabled the
+// natives opening network connections
+func (i *Interpreter) CheckNetwork(function string) error {
+	if !i.Host.Network {
+		return errors.New(function + " is disabled by the host")
+	}
+	return nil
+}
+
+// Socket is a TCP connection opened with connect
+type Socket struct {
+	Address string
+	conn    net.Conn
+}
+
+func (s *Socket) String() string {
+	return "<socket " + s.Address + ">"
+}
+
+// connect gives a promise of a socket connected to a host:port
+// address
+func connect(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckNetwork("connect"); err != nil {
+		return nil, err
+	}
+	address, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("connect expects an address that is a string but got " + i.stringify(args[0]))
+	}
+	return i.Background("connect", func() (interface{}, error) {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			return nil, err
+		}
+		return &Socket{Address: address, conn: conn}, nil
+	}), nil
+}
+
+// GetSocketMethod returns the method of a socket bound to it,
+// read and write give promises
+func (i *Interpreter) GetSocketMethod(s *Socket, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "read":
+		// read gives nil once the other side closes the connection
+		return NewNativeFunction("read", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return i.Background("read", func() (interface{}, error) {
+				buffer := make([]byte, 4096)
+				n, err := s.conn.Read(buffer)
+				if n > 0 {
+					return string(buffer[:n]), nil
+				}
+				if err == io.EOF {
+					return nil, nil
+				}
+				return nil, err
+			}), nil
+		}), nil
+	case "write":
+		return NewNativeFunction("write", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			data, ok := args[0].(string)
+			if !ok {
+				return nil, errors.New("write expects a string but got " + i.stringify(args[0]))
+			}
+			return i.Background("write", func() (interface{}, error) {
+				_, err := s.conn.Write([]byte(data))
+				return nil, err
+			}), nil
+		}), nil
+	case "close":
+		return NewNativeFunction("close", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return nil, s.conn.Close()
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Sockets have no method "+name.Lexeme)
+}
@@ -0,0 +1,196 @@
+package interpreter_test
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/lang"
+)
+
+func TestEventLoopOrder(t *testing.T) {
+	source := `
+var order = "";
+async def work(name, ms) {
+  await sleep(ms);
+  order = order + name + " ";
+}
+def timer() { order = order + "timer "; }
+setTimeout(timer, 15);
+work("slow", 30);
+work("fast", 5);
+order = order + "main ";
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := global(t, execution, "order"), "main fast timer slow "; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSetTimeoutPromise(t *testing.T) {
+	execution, err := run(t, `def answer() { return 42; } var result = await setTimeout(answer, 1);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "result"); got != int64(42) {
+		t.Errorf("got %v, want 42", got)
+	}
+
+	_, err = run(t, `def fail() { return [][0]; } await setTimeout(fail, 1);`)
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("expected the error of the callback but got %v", err)
+	}
+}
+
+func TestAwait(t *testing.T) {
+	execution, err := run(t, `
+var order = "";
+async def inner() {
+  order = order + "inner ";
+  await sleep(1);
+  return 2;
+}
+async def outer() {
+  order = order + "outer ";
+  var value = await inner();
+  order = order + "resumed ";
+  return value * 10;
+}
+var promise = outer();
+order = order + "main ";
+var result = await promise;
+var again = await promise;
+var plain = await 5;
+var printed = "{}".format(promise);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		// An async function only starts once the caller waits
+		{"order", "main outer inner resumed "},
+		{"result", int64(20)},
+		{"again", int64(20)},
+		{"plain", int64(5)},
+		{"printed", "<promise outer>"},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestUnhandledRejection(t *testing.T) {
+	_, err := run(t, `
+async def fail() { return [][0]; }
+def start() { fail(); }
+start();
+`)
+	if err == nil {
+		t.Fatal("expected the rejection to be reported")
+	}
+	for _, want := range []string{"Unhandled rejection of fail", "out of range", "in fail called at line 3", "in start called at line 4"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+
+	// A rejection that is awaited is not reported again
+	_, err = run(t, `
+async def fail() { return [][0]; }
+var p = fail();
+await p;
+`)
+	if err == nil || strings.Contains(err.Error(), "Unhandled") {
+		t.Errorf("expected only the awaited error but got %v", err)
+	}
+}
+
+func TestAsyncFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	execution, err := run(t, `
+var file = "`+file+`";
+await writeFileAsync(file, "héllo");
+var text = await readFileAsync(file);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "text"); got != "héllo" {
+		t.Errorf("got %v", got)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	_, err = run(t, `await readFileAsync("`+missing+`");`)
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("expected an error naming the file but got %v", err)
+	}
+}
+
+func TestTimerDurationRange(t *testing.T) {
+	sources := []string{
+		"await sleep(1e300);",
+		"await sleep(-1);",
+		"def f() {} setTimeout(f, 1e300);",
+		`http.request({"url": "http://localhost", "timeout": 1e300});`,
+	}
+	for _, source := range sources {
+		_, err := run(t, source)
+		if err == nil || !strings.Contains(err.Error(), "milliseconds") {
+			t.Errorf("%s: expected an error about the milliseconds but got %v", source, err)
+		}
+	}
+}
+
+func TestConnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buffer := make([]byte, 64)
+		n, _ := conn.Read(buffer)
+		conn.Write(buffer[:n])
+	}()
+
+	source := `
+var socket = await connect("` + listener.Addr().String() + `");
+await socket.write("ping");
+var reply = await socket.read();
+socket.close();
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "reply"); got != "ping" {
+		t.Errorf("got %v, want ping", got)
+	}
+}
+
+func TestConnectDisabled(t *testing.T) {
+	program, err := lang.Compile(`connect("127.0.0.1:1");`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execution := interpreter.NewExecution(program)
+	execution.Host.Network = false
+	if err := program.Execute(execution); err == nil || !strings.Contains(err.Error(), "disabled by the host") {
+		t.Errorf("expected connect to be disabled but got %v", err)
+	}
+}
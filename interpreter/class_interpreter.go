@@ -3,6 +3,7 @@ package interpreter
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/Atul-Ranjan12/parser/expressions"
 	"github.com/Atul-Ranjan12/token"
@@ -14,6 +15,14 @@ const CLASS_CONSTRUCTOR_NAME string = "construct"
 type Class struct {
 	Name    string
 	Methods map[string]*Function
+	// Traits holds the traits the class implements
+	Traits []*Trait
+	// Static methods are called on the class itself
+	StaticMethods map[string]*Function
+	// Getters and setters are called when a property
+	// is read or assigned
+	Getters map[string]*Function
+	Setters map[string]*Function
 }
 
 // Instance represents an instance of the class
@@ -21,8 +30,13 @@ type Class struct {
 type Instance struct {
 	// Associated to the class
 	ClassName *Class
-	// Add fields
+	// Add fields, guarded by the mutex since instances
+	// can be shared between tasks
 	Fields map[string]interface{}
+	mutex  sync.RWMutex
+	// A frozen instance can not have its fields set, the
+	// flag is guarded by the mutex as well
+	frozen bool
 }
 
 // Class implements the callable interface
@@ -31,8 +45,11 @@ var _ Callable = (*Class)(nil)
 // NewClass creates a new class
 func NewClass(name string, methods map[string]*Function) *Class {
 	return &Class{
-		Name:    name,
-		Methods: methods,
+		Name:          name,
+		Methods:       methods,
+		StaticMethods: make(map[string]*Function),
+		Getters:       make(map[string]*Function),
+		Setters:       make(map[string]*Function),
 	}
 }
 
@@ -73,13 +90,31 @@ func (c *Class) Call(interpreter *Interpreter, arguments []interface{}) (interfa
 	return classInstance, nil
 }
 
-// Arity returns the arity for the class
-func (c *Class) Arity() int {
+// MinArity returns the minimum arity of the constructor
+func (c *Class) MinArity() int {
 	constructor, err := c.FindMethod(CLASS_CONSTRUCTOR_NAME)
 	if err != nil || constructor == nil {
 		return 0
 	}
-	return constructor.Arity()
+	return constructor.MinArity()
+}
+
+// MaxArity returns the maximum arity of the constructor
+func (c *Class) MaxArity() int {
+	constructor, err := c.FindMethod(CLASS_CONSTRUCTOR_NAME)
+	if err != nil || constructor == nil {
+		return 0
+	}
+	return constructor.MaxArity()
+}
+
+// ParameterNames returns the parameter names of the constructor
+func (c *Class) ParameterNames() []string {
+	constructor, err := c.FindMethod(CLASS_CONSTRUCTOR_NAME)
+	if err != nil || constructor == nil {
+		return nil
+	}
+	return constructor.ParameterNames()
 }
 
 // FindMethod finds the method and returns it
@@ -93,7 +128,7 @@ func (c *Class) FindMethod(name string) (*Function, error) {
 
 // Get function returns a property of an instance
 func (ins *Instance) Get(name *token.Token) (interface{}, error) {
-	if v, ok := ins.Fields[name.Lexeme]; ok {
+	if v, ok := ins.Field(name.Lexeme); ok {
 		return v, nil
 	}
 
@@ -110,9 +145,49 @@ func (ins *Instance) Get(name *token.Token) (interface{}, error) {
 	return nil, errors.New(fmt.Sprintf("Property %s does not exist: ", name.Lexeme))
 }
 
-// Set function sets a property of an instance
-func (ins *Instance) Set(name *token.Token, value interface{}) {
+// Set function sets a property of an instance, false is returned
+// when the instance is frozen
+func (ins *Instance) Set(name *token.Token, value interface{}) bool {
+	ins.mutex.Lock()
+	defer ins.mutex.Unlock()
+	if ins.frozen {
+		return false
+	}
 	ins.Fields[name.Lexeme] = value
+	return true
+}
+
+// Freeze makes the fields of the instance immutable
+func (ins *Instance) Freeze() {
+	ins.mutex.Lock()
+	defer ins.mutex.Unlock()
+	ins.frozen = true
+}
+
+// IsFrozen checks if the fields of the instance can not be set
+func (ins *Instance) IsFrozen() bool {
+	ins.mutex.RLock()
+	defer ins.mutex.RUnlock()
+	return ins.frozen
+}
+
+// Field returns the value of a field of an instance
+func (ins *Instance) Field(name string) (interface{}, bool) {
+	ins.mutex.RLock()
+	defer ins.mutex.RUnlock()
+	value, ok := ins.Fields[name]
+	return value, ok
+}
+
+// CopyFields returns a copy of the fields of an instance
+func (ins *Instance) CopyFields() map[string]interface{} {
+	ins.mutex.RLock()
+	defer ins.mutex.RUnlock()
+	fields := make(map[string]interface{}, len(ins.Fields))
+	for name, value := range ins.Fields {
+		fields[name] = value
+	}
+	return fields
 }
 
 // VisitClassStmt handles interpretation of calss
@@ -127,22 +202,57 @@ func (i *Interpreter) VisitClassStmt(stmt *expressions.Class) (interface{}, erro
 	}
 
 	class := NewClass(stmt.Name.Lexeme, methods)
+	for _, method := range stmt.StaticMethods {
+		class.StaticMethods[method.Name.Lexeme] = NewFunction(method, i.Environment)
+	}
+	for _, getter := range stmt.Getters {
+		class.Getters[getter.Name.Lexeme] = NewFunction(getter, i.Environment)
+	}
+	for _, setter := range stmt.Setters {
+		class.Setters[setter.Name.Lexeme] = NewFunction(setter, i.Environment)
+	}
+	for _, traitName := range stmt.Traits {
+		value, err := i.Evaluate(traitName)
+		if err != nil {
+			return nil, err
+		}
+		trait, ok := value.(*Trait)
+		if !ok {
+			return nil, i.RuntimeError(traitName.Name, "A struct can only implement a trait")
+		}
+		class.Traits = append(class.Traits, trait)
+	}
 
 	i.Environment.Assign(stmt.Name, class)
 
 	return nil, nil
 }
 
-// VisitClassStmt handles interpretation of calss
+// VisitGetExpr handles getting a property of an object
 func (i *Interpreter) VisitGetExpr(expr *expressions.Get) (interface{}, error) {
 	object, err := i.Evaluate(expr.Object)
 	if err != nil {
 		return nil, err
 	}
 
+	// Optional chaining short circuits on a nil object
+	if expr.Optional && object == nil {
+		return nil, nil
+	}
+
+	return i.GetProperty(object, &expr.Name)
+}
+
+// GetProperty returns a property of an evaluated object
+func (i *Interpreter) GetProperty(object interface{}, name *token.Token) (interface{}, error) {
 	// Object has to be an instance of Instance
 	if objectInstance, ok := object.(*Instance); ok {
-		val, err := objectInstance.Get(&expr.Name)
+		// A getter is called without parentheses
+		if getter, ok := objectInstance.ClassName.Getters[name.Lexeme]; ok {
+			return getter.Bind(objectInstance).Call(i, nil)
+		}
+
+		val, err := objectInstance.Get(name)
 		if err != nil {
 			return nil, err
 		}
@@ -150,10 +260,61 @@ func (i *Interpreter) VisitGetExpr(expr *expressions.Get) (interface{}, error) {
 		return val, nil
 	}
 
+	// Strings have methods bound to the string value
+	if s, ok := object.(string); ok {
+		return i.GetStringMethod(s, name)
+	}
+
+	if generator, ok := object.(*Generator); ok {
+		return i.GetGeneratorMethod(generator, name)
+	}
+	if task, ok := object.(*Task); ok {
+		return i.GetTaskMethod(task, name)
+	}
+	if channel, ok := object.(*Channel); ok {
+		return i.GetChannelMethod(channel, name)
+	}
+	if socket, ok := object.(*Socket); ok {
+		return i.GetSocketMethod(socket, name)
+	}
+	if server, ok := object.(*Server); ok {
+		return i.GetServerMethod(server, name)
+	}
+	if module, ok := object.(*Module); ok {
+		return module.Get(i, name)
+	}
+	if t, ok := object.(*Time); ok {
+		return i.GetTimeProperty(t, name)
+	}
+	if d, ok := object.(*Duration); ok {
+		return i.GetDurationProperty(d, name)
+	}
+	if regex, ok := object.(*Regex); ok {
+		return i.GetRegexMethod(regex, name)
+	}
+	if _, ok := object.(*Promise); ok {
+		return nil, i.RuntimeError(*name, "Promises have no properties, await the promise first")
+	}
+
+	// Variants are properties of the enum
+	if enum, ok := object.(*Enum); ok {
+		return enum.Get(i, name)
+	}
+	if value, ok := object.(*EnumValue); ok {
+		return value.Get(i, name)
+	}
+
+	// Static methods are properties of the class
+	if class, ok := object.(*Class); ok {
+		if method, ok := class.StaticMethods[name.Lexeme]; ok {
+			return method, nil
+		}
+		return nil, i.RuntimeError(*name, "Undefined static method "+name.Lexeme+" of struct "+class.Name)
+	}
+
 	return nil, errors.New("Only objects have properties")
 }
 
-// VisitSetExpr handles setting fields in objects
 // VisitSetExpr handles setting fields in objects
 func (i *Interpreter) VisitSetExpr(expr *expressions.Set) (interface{}, error) {
 	object, err := i.Evaluate(expr.Object)
@@ -161,21 +322,40 @@ func (i *Interpreter) VisitSetExpr(expr *expressions.Set) (interface{}, error) {
 		return nil, err
 	}
 
-	objectInstance, ok := object.(*Instance)
-	if !ok {
-		return nil, errors.New("Only instances have fields")
-	}
-
 	val, err := i.Evaluate(expr.Value)
 	if err != nil {
 		return nil, err
 	}
 
-	objectInstance.Set(&expr.Name, val)
+	if err := i.SetProperty(object, &expr.Name, val); err != nil {
+		return nil, err
+	}
 
 	return val, nil
 }
 
+// SetProperty sets a field of an evaluated object
+func (i *Interpreter) SetProperty(object interface{}, name *token.Token, value interface{}) error {
+	objectInstance, ok := object.(*Instance)
+	if !ok {
+		return errors.New("Only instances have fields")
+	}
+
+	// A setter is called with the assigned value
+	if setter, ok := objectInstance.ClassName.Setters[name.Lexeme]; ok {
+		_, err := setter.Bind(objectInstance).Call(i, []interface{}{value})
+		return err
+	}
+	if _, ok := objectInstance.ClassName.Getters[name.Lexeme]; ok {
+		return i.RuntimeError(*name, "Can not set property "+name.Lexeme+" which only has a getter")
+	}
+
+	if !objectInstance.Set(name, value) {
+		return i.RuntimeError(*name, "Can not set field of a frozen instance of "+objectInstance.ClassName.Name)
+	}
+	return nil
+}
+
 // VisitThisExpr handles when it encounters a this function
 func (i *Interpreter) VisitThisExpr(expr *expressions.This) (interface{}, error) {
 
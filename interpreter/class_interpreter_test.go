@@ -0,0 +1,68 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStaticMethodsAndAccessors(t *testing.T) {
+	execution, err := run(t, `
+struct Rect {
+  construct(w, h) { this.w = w; this.h = h; this.sets = 0; }
+  static square(side) { return Rect(side, side); }
+  static unit() { return Rect.square(1); }
+  get area() { return this.w * this.h; }
+  set width(w) { this.w = w; this.sets = this.sets + 1; }
+  get width() { return this.w; }
+  grow() { this.width = this.w * 2; return this; }
+}
+var square = Rect.square(3);
+var area = square.area;
+var unit = Rect.unit().area;
+var r = Rect(2, 5);
+r.width = 4;
+var setArea = r.area;
+var width = r.width;
+var grown = r.grow().area;
+var sets = r.sets;
+var factory = Rect.square;
+var fromValue = factory(2).area;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"area", int64(9)},
+		{"unit", int64(1)},
+		{"setArea", int64(20)},
+		{"width", int64(4)},
+		{"grown", int64(40)},
+		{"sets", int64(2)},
+		{"fromValue", int64(4)},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestStaticMethodsAndAccessorsErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`struct P { construct() {} } def f(s) { return s.nope(); } f(P);`, "Undefined static method nope of struct P"},
+		{`struct P { construct() {} get a() { return 1; } } def f(p) { p.a = 2; } f(P());`, "Can not set property a which only has a getter"},
+		{`struct P { construct() {} get a() { return [][0]; } } P().a;`, "out of range"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Atul-Ranjan12/environment"
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles destructuring declarations and
+// assignments to several targets at once
+
+// VisitDestructureStmt declares the variables bound by a pattern
+func (i *Interpreter) VisitDestructureStmt(stmt *expressions.Destructure) (interface{}, error) {
+	value, err := i.Evaluate(stmt.Initializer)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, i.BindPattern(stmt.Pattern, value, i.Environment)
+}
+
+// BindPattern defines the variables of a pattern in env, unlike
+// MatchPattern a value of the wrong shape is a runtime error
+func (i *Interpreter) BindPattern(pattern expressions.Pattern, value interface{}, env *environment.Environment) error {
+	switch p := pattern.(type) {
+	case *expressions.Wildcard:
+		return nil
+
+	case *expressions.Binding:
+		env.Define(p.Name.Lexeme, value)
+		return nil
+
+	case *expressions.Constant:
+		equal, err := i.IsEqual(p.Value, value)
+		if err != nil {
+			return err
+		}
+		if !equal {
+			return i.RuntimeError(p.Token, fmt.Sprintf("Expected %s but got %s", i.stringify(p.Value), i.stringify(value)))
+		}
+		return nil
+
+	case *expressions.Sequence:
+		list, ok := value.(*List)
+		if !ok {
+			return i.RuntimeError(p.Bracket, "Can only destructure a list with a list pattern, got "+i.stringify(value))
+		}
+		if list.Len() != len(p.Elements) {
+			return i.RuntimeError(p.Bracket, fmt.Sprintf("Expected a list of %d elements but got %d", len(p.Elements), list.Len()))
+		}
+		for index, element := range p.Elements {
+			if err := i.BindPattern(element, list.Elements[index], env); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *expressions.Record:
+		if p.Class != nil {
+			class, err := i.EvaluateIn(p.Class, env)
+			if err != nil {
+				return err
+			}
+			if instance, ok := value.(*Instance); !ok || instance.ClassName != class {
+				return i.RuntimeError(p.Class.Name, "Expected an instance of "+p.Class.Name.Lexeme+" but got "+i.stringify(value))
+			}
+		}
+
+		for index, field := range p.Fields {
+			fieldValue, err := i.FieldOf(value, field)
+			if err != nil {
+				return err
+			}
+			if err := i.BindPattern(p.Patterns[index], fieldValue, env); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// FieldOf returns a field of an instance or the value of a
+// string key of a map for a struct pattern
+func (i *Interpreter) FieldOf(value interface{}, field token.Token) (interface{}, error) {
+	switch v := value.(type) {
+	case *Instance:
+		fieldValue, ok := v.Field(field.Lexeme)
+		if !ok {
+			return nil, i.RuntimeError(field, "Instance of "+v.ClassName.Name+" has no field "+field.Lexeme)
+		}
+		return fieldValue, nil
+	case *Map:
+		fieldValue, ok, _ := v.Get(field.Lexeme)
+		if !ok {
+			return nil, i.RuntimeError(field, "Map has no key "+field.Lexeme)
+		}
+		return fieldValue, nil
+	}
+	return nil, i.RuntimeError(field, "Can only destructure fields of an instance or a map, got "+i.stringify(value))
+}
+
+// VisitMultiAssignStmt evaluates every value before assigning
+// any target, so a, b = b, a swaps the two variables
+func (i *Interpreter) VisitMultiAssignStmt(stmt *expressions.MultiAssign) (interface{}, error) {
+	values := make([]interface{}, 0, len(stmt.Targets))
+	for _, valueExpr := range stmt.Values {
+		value, err := i.Evaluate(valueExpr)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	// A single value is unpacked into the targets
+	if len(values) != len(stmt.Targets) {
+		list, ok := values[0].(*List)
+		if !ok {
+			return nil, i.RuntimeError(stmt.Equals, "Can only unpack a list into several targets, got "+i.stringify(values[0]))
+		}
+		if list.Len() != len(stmt.Targets) {
+			return nil, i.RuntimeError(stmt.Equals, fmt.Sprintf("Expected a list of %d elements but got %d", len(stmt.Targets), list.Len()))
+		}
+		values = list.Elements
+	}
+
+	for index, target := range stmt.Targets {
+		switch t := target.(type) {
+		case *expressions.Variable:
+			if err := i.AssignVariable(t, t.Name, values[index]); err != nil {
+				return nil, err
+			}
+		case *expressions.Get:
+			object, err := i.Evaluate(t.Object)
+			if err != nil {
+				return nil, err
+			}
+			if err := i.SetProperty(object, &t.Name, values[index]); err != nil {
+				return nil, err
+			}
+		case *expressions.Index:
+			object, err := i.Evaluate(t.Object)
+			if err != nil {
+				return nil, err
+			}
+			key, err := i.Evaluate(t.Index)
+			if err != nil {
+				return nil, err
+			}
+			if err := i.SetIndex(t.Bracket, object, key, values[index]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,83 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDestructuring(t *testing.T) {
+	execution, err := run(t, `
+struct Point { construct(x, y) { this.x = x; this.y = y; } }
+var [a, b] = [1, 2];
+var [first, [second, _]] = ["one", ["two", "ignored"]];
+var {x, y: height} = Point(3, 4);
+var {name} = {"name": "lang", "version": 1};
+var [Point{x: px}] = [Point(5, 6)];
+var [one, 2] = [1, 2];
+var swapLeft = "left";
+var swapRight = "right";
+swapLeft, swapRight = swapRight, swapLeft;
+var p = Point(0, 0);
+var xs = [0, 0];
+p.x, xs[1] = [7, 8];
+var assigned = p.x;
+var sum = 0;
+def pair() { return [10, 20]; }
+var u = 0;
+var v = 0;
+u, v = pair();
+sum = u + v;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"a", int64(1)},
+		{"b", int64(2)},
+		{"first", "one"},
+		{"second", "two"},
+		{"x", int64(3)},
+		{"height", int64(4)},
+		{"name", "lang"},
+		{"px", int64(5)},
+		{"one", int64(1)},
+		{"swapLeft", "right"},
+		{"swapRight", "left"},
+		{"sum", int64(30)},
+		{"assigned", int64(7)},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+	if got, err := execution.Stringify(global(t, execution, "xs")); err != nil || got != "[0, 8]" {
+		t.Errorf("got xs %v", got)
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`var [a, b] = [[1, 2, 3]][0];`, "Expected a list of 2 elements but got 3"},
+		{`var [a, b] = [1][0];`, "Can only destructure a list with a list pattern, got 1"},
+		{`var [a, 2] = [[1, 3]][0];`, "Expected 2 but got 3"},
+		{`struct P { construct() {} } var {x} = P();`, "Instance of P has no field x"},
+		{`var {x} = [{"y": 1}][0];`, "Map has no key x"},
+		{`var {x} = [1][0];`, "Can only destructure fields of an instance or a map, got 1"},
+		{`struct P { construct() {} } struct Q { construct() {} } var [P{}] = [Q()];`, "Expected an instance of P but got"},
+		{`var a = 0; var b = 0; a, b = [[1]][0];`, "Expected a list of 2 elements but got 1"},
+		{`var a = 0; var b = 0; a, b = [1][0];`, "Can only unpack a list into several targets, got 1"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// Enum represents an enum in runtime
+type Enum struct {
+	Name     string
+	Variants map[string]*EnumVariant
+}
+
+// EnumVariant is a variant of an enum. A variant that carries
+// values is called to create an EnumValue, a variant without
+// values has a single value
+type EnumVariant struct {
+	Enum    *Enum
+	Name    string
+	Ordinal int
+	// Fields is nil for a variant that carries no values
+	Fields []string
+	Value  *EnumValue
+}
+
+// EnumValue is a value of an enum, its payload holds the values
+// carried by its variant
+type EnumValue struct {
+	Variant *EnumVariant
+	Payload []interface{}
+}
+
+// EnumVariant implements the callable interface
+var _ Callable = (*EnumVariant)(nil)
+var _ Parameterized = (*EnumVariant)(nil)
+
+func (e *Enum) String() string {
+	return "<enum " + e.Name + ">"
+}
+
+func (v *EnumVariant) String() string {
+	return "<variant " + v.Enum.Name + "." + v.Name + ">"
+}
+
+// MinArity returns the number of values carried by the variant
+func (v *EnumVariant) MinArity() int {
+	return len(v.Fields)
+}
+
+// MaxArity returns the number of values carried by the variant
+func (v *EnumVariant) MaxArity() int {
+	return len(v.Fields)
+}
+
+// ParameterNames returns the fields of the variant
+func (v *EnumVariant) ParameterNames() []string {
+	return v.Fields
+}
+
+// Call creates a value of the variant
+func (v *EnumVariant) Call(i *Interpreter, arguments []interface{}) (interface{}, error) {
+	return &EnumValue{Variant: v, Payload: arguments}, nil
+}
+
+// VisitEnumStmt defines an enum
+func (i *Interpreter) VisitEnumStmt(stmt *expressions.Enum) (interface{}, error) {
+	enum := &Enum{Name: stmt.Name.Lexeme, Variants: make(map[string]*EnumVariant)}
+	for index, name := range stmt.Variants {
+		variant := &EnumVariant{Enum: enum, Name: name.Lexeme, Ordinal: index}
+		if stmt.Fields[index] == nil {
+			variant.Value = &EnumValue{Variant: variant}
+		} else {
+			variant.Fields = make([]string, len(stmt.Fields[index]))
+			for position, field := range stmt.Fields[index] {
+				variant.Fields[position] = field.Lexeme
+			}
+		}
+		enum.Variants[name.Lexeme] = variant
+	}
+
+	i.Environment.Define(stmt.Name.Lexeme, enum)
+	return nil, nil
+}
+
+// Get returns a variant of an enum, the value of a variant that
+// carries no values is returned directly
+func (e *Enum) Get(i *Interpreter, name *token.Token) (interface{}, error) {
+	variant, ok := e.Variants[name.Lexeme]
+	if !ok {
+		return nil, i.RuntimeError(*name, "Undefined variant "+name.Lexeme+" of enum "+e.Name)
+	}
+	if variant.Value != nil {
+		return variant.Value, nil
+	}
+	return variant, nil
+}
+
+// Get returns a property of an enum value, its name, ordinal,
+// payload or one of the values it carries by field name
+func (v *EnumValue) Get(i *Interpreter, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "name":
+		return v.Variant.Name, nil
+	case "ordinal":
+		return int64(v.Variant.Ordinal), nil
+	case "payload":
+		payload := make([]interface{}, len(v.Payload))
+		copy(payload, v.Payload)
+		return NewList(payload), nil
+	}
+
+	for index, field := range v.Variant.Fields {
+		if field == name.Lexeme {
+			return v.Payload[index], nil
+		}
+	}
+	return nil, i.RuntimeError(*name, "Undefined property "+name.Lexeme+" of "+v.Variant.Enum.Name+"."+v.Variant.Name)
+}
+
+// stringifyEnumValue writes an enum value as Enum.Variant
+// followed by the values it carries
+func (i *Interpreter) stringifyEnumValue(v *EnumValue, visiting map[interface{}]bool) (string, error) {
+	name := v.Variant.Enum.Name + "." + v.Variant.Name
+	if v.Variant.Fields == nil {
+		return name, nil
+	}
+
+	values := make([]string, len(v.Payload))
+	for index, value := range v.Payload {
+		s, err := i.stringifyElement(value, visiting)
+		if err != nil {
+			return "", err
+		}
+		values[index] = s
+	}
+	return name + "(" + strings.Join(values, ", ") + ")", nil
+}
+
+// isEqualEnumValue checks if two enum values are of the same
+// variant and carry equal values
+func (i *Interpreter) isEqualEnumValue(a *EnumValue, b interface{}, comparing map[comparison]bool) (bool, error) {
+	other, ok := b.(*EnumValue)
+	if !ok || a.Variant != other.Variant {
+		return false, nil
+	}
+	for index := range a.Payload {
+		if equal, err := i.isEqual(a.Payload[index], other.Payload[index], comparing); !equal || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,81 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnums(t *testing.T) {
+	execution, err := run(t, `
+enum Shape { Circle(radius), Rect(w, h), Empty }
+enum Color { Red, Green }
+def area(shape) {
+  var result = 0;
+  match (shape) {
+    Shape.Circle(r) => result = 3 * r * r;
+    Shape.Rect(w, h) => result = w * h;
+    Shape.Empty => result = 0;
+  }
+  return result;
+}
+var rect = Shape.Rect(2, 3);
+var name = rect.name;
+var ordinal = rect.ordinal;
+var payload = "{}".format(rect.payload);
+var width = rect.w;
+var keywords = Shape.Rect(h: 4, w: 1).h;
+var areas = area(rect) + area(Shape.Circle(1)) + area(Shape.Empty);
+var printed = "{} {} {} {}".format(rect, Shape.Empty, Shape.Circle, Shape);
+var same = Shape.Rect(2, 3) == rect;
+var mixed = Shape.Rect(2, 3.0) == rect;
+var differentValues = Shape.Rect(3, 2) == rect;
+var differentVariant = Shape.Empty == Color.Red;
+var single = Color.Red == Color.Red;
+var emptyOrdinal = Shape.Empty.ordinal;
+var emptyPayload = len(Shape.Empty.payload);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"name", "Rect"},
+		{"ordinal", int64(1)},
+		{"payload", "[2, 3]"},
+		{"width", int64(2)},
+		{"keywords", int64(4)},
+		{"areas", int64(9)},
+		{"printed", "Shape.Rect(2, 3) Shape.Empty <variant Shape.Circle> <enum Shape>"},
+		{"same", true},
+		{"mixed", true},
+		{"differentValues", false},
+		{"differentVariant", false},
+		{"single", true},
+		{"emptyOrdinal", int64(2)},
+		{"emptyPayload", int64(0)},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestEnumErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`enum E { A, B(x) } def f(e) { return e.C; } f(E);`, "Undefined variant C of enum E"},
+		{`enum E { A, B(x) } def f(v) { return v.y; } f(E.B(1));`, "Undefined property y of E.B"},
+		{`enum E { A, B(x) } [E.B][0](1, 2);`, "Expected 1 arguments but got 2."},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
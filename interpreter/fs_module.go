@@ -0,0 +1,244 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// This file handles the fs module reading and writing files,
+// which the host can disable, and the path module working on
+// file paths. mkdir creates the directories above the path,
+// remove removes a file or an empty directory and stat gives a
+// map of the name, size, isDir, mode and modified time. A
+// failing call is a runtime error naming its line, the call
+// and the path
+
+// DefineFileSystemModules defines the fs and path modules
+func (i *Interpreter) DefineFileSystemModules() {
+	fs := NewModule("fs")
+	fs.Function("readFile", 1, 1, withLine(fsReadFile))
+	fs.Function("writeFile", 2, 2, withLine(fsWriteFile))
+	fs.Function("appendFile", 2, 2, withLine(fsAppendFile))
+	fs.Function("exists", 1, 1, withLine(fsExists))
+	fs.Function("listDir", 1, 1, withLine(fsListDir))
+	fs.Function("mkdir", 1, 1, withLine(fsMkdir))
+	fs.Function("remove", 1, 1, withLine(fsRemove))
+	fs.Function("stat", 1, 1, withLine(fsStat))
+	fs.Function("glob", 1, 1, withLine(fsGlob))
+	i.Globals.Define(fs.Name, fs)
+
+	path := NewModule("path")
+	path.Function("join", 1, VARIADIC, withLine(pathJoin))
+	path.Function("base", 1, 1, withLine(pathFunction("path.base", filepath.Base)))
+	path.Function("dir", 1, 1, withLine(pathFunction("path.dir", filepath.Dir)))
+	path.Function("ext", 1, 1, withLine(pathFunction("path.ext", filepath.Ext)))
+	i.Globals.Define(path.Name, path)
+}
+
+// withLine adds the line of the call to the errors of a native
+func withLine(native func(i *Interpreter, args []interface{}) (interface{}, error)) func(i *Interpreter, args []interface{}) (interface{}, error) {
+	return func(i *Interpreter, args []interface{}) (interface{}, error) {
+		value, err := native(i, args)
+		if err != nil && len(i.Frames) > 0 {
+			return nil, fmt.Errorf("[line %d] %s", i.Frames[len(i.Frames)-1].Line, err)
+		}
+		return value, err
+	}
+}
+
+// CheckFileSystem returns an error when the host has disabled
+// the natives reading and writing files
+func (i *Interpreter) CheckFileSystem(function string) error {
+	if !i.Host.FileSystem {
+		return errors.New(function + " is disabled by the host")
+	}
+	return nil
+}
+
+// fsPathArg checks that the host allows the file system and that
+// the argument is a path
+func fsPathArg(i *Interpreter, function string, arg interface{}) (string, error) {
+	if err := i.CheckFileSystem(function); err != nil {
+		return "", err
+	}
+	return stringArg(function, arg)
+}
+
+// fsError names the native an error of the file system came from
+func fsError(function string, err error) error {
+	return errors.New(function + ": " + err.Error())
+}
+
+func fsReadFile(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.readFile", args[0])
+	if err != nil {
+		return nil, err
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fsError("fs.readFile", err)
+	}
+	return string(contents), nil
+}
+
+func fsWriteFile(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.writeFile", args[0])
+	if err != nil {
+		return nil, err
+	}
+	contents, err := stringArg("fs.writeFile", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		return nil, fsError("fs.writeFile", err)
+	}
+	return nil, nil
+}
+
+// fsAppendFile adds to the end of a file, creating it if needed
+func fsAppendFile(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.appendFile", args[0])
+	if err != nil {
+		return nil, err
+	}
+	contents, err := stringArg("fs.appendFile", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fsError("fs.appendFile", err)
+	}
+	_, err = file.WriteString(contents)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fsError("fs.appendFile", err)
+	}
+	return nil, nil
+}
+
+func fsExists(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.exists", args[0])
+	if err != nil {
+		return nil, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return nil, fsError("fs.exists", err)
+	}
+	return true, nil
+}
+
+// fsListDir gives the sorted names of the entries of a directory
+func fsListDir(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.listDir", args[0])
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fsError("fs.listDir", err)
+	}
+	names := make([]interface{}, len(entries))
+	for index, entry := range entries {
+		names[index] = entry.Name()
+	}
+	return NewList(names), nil
+}
+
+// fsMkdir creates a directory and the directories above it
+func fsMkdir(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.mkdir", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fsError("fs.mkdir", err)
+	}
+	return nil, nil
+}
+
+// fsRemove removes a file or an empty directory
+func fsRemove(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.remove", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, fsError("fs.remove", err)
+	}
+	return nil, nil
+}
+
+// fsStat gives a map with the name, size, isDir, mode and the
+// modification time in seconds since the epoch of a path
+func fsStat(i *Interpreter, args []interface{}) (interface{}, error) {
+	path, err := fsPathArg(i, "fs.stat", args[0])
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fsError("fs.stat", err)
+	}
+
+	stat := NewMap()
+	stat.Set("name", info.Name())
+	stat.Set("size", info.Size())
+	stat.Set("isDir", info.IsDir())
+	stat.Set("mode", int64(info.Mode().Perm()))
+	stat.Set("modified", float64(info.ModTime().UnixNano())/1e9)
+	return stat, nil
+}
+
+// fsGlob gives the sorted paths matching a pattern
+func fsGlob(i *Interpreter, args []interface{}) (interface{}, error) {
+	pattern, err := fsPathArg(i, "fs.glob", args[0])
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fsError("fs.glob", err)
+	}
+	sort.Strings(matches)
+	paths := make([]interface{}, len(matches))
+	for index, match := range matches {
+		paths[index] = match
+	}
+	return NewList(paths), nil
+}
+
+// pathJoin joins the parts of a path with the separator
+func pathJoin(i *Interpreter, args []interface{}) (interface{}, error) {
+	parts := make([]string, len(args))
+	for index, arg := range args {
+		part, err := stringArg("path.join", arg)
+		if err != nil {
+			return nil, err
+		}
+		parts[index] = part
+	}
+	return filepath.Join(parts...), nil
+}
+
+// pathFunction creates a path function of one path
+func pathFunction(function string, apply func(string) string) func(i *Interpreter, args []interface{}) (interface{}, error) {
+	return func(i *Interpreter, args []interface{}) (interface{}, error) {
+		path, err := stringArg(function, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return apply(path), nil
+	}
+}
@@ -0,0 +1,82 @@
+package interpreter_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/lang"
+)
+
+func TestFileSystem(t *testing.T) {
+	dir := t.TempDir()
+	source := `
+var dir = "` + dir + `";
+fs.mkdir(path.join(dir, "a", "b"));
+var file = path.join(dir, "a", "notes.txt");
+fs.writeFile(file, "one");
+fs.appendFile(file, " two");
+var contents = fs.readFile(file);
+var exists = fs.exists(file);
+var entries = fs.listDir(path.join(dir, "a"));
+var size = fs.stat(file)["size"];
+var isDir = fs.stat(path.join(dir, "a", "b"))["isDir"];
+var matches = len(fs.glob(path.join(dir, "a", "*.txt")));
+fs.remove(file);
+var removed = !fs.exists(file);
+var base = path.base(file);
+var ext = path.ext(file);
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"contents", "one two"},
+		{"exists", true},
+		{"size", int64(7)},
+		{"isDir", true},
+		{"matches", int64(1)},
+		{"removed", true},
+		{"base", "notes.txt"},
+		{"ext", ".txt"},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+	entries := global(t, execution, "entries").(*interpreter.List)
+	if entries.Len() != 2 || entries.Elements[0] != "b" || entries.Elements[1] != "notes.txt" {
+		t.Errorf("got entries %v", entries.Elements)
+	}
+}
+
+func TestFileSystemErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	_, err := run(t, "var a = 1;\nfs.readFile(\""+missing+"\");")
+	if err == nil || !strings.HasPrefix(err.Error(), "[line 2] fs.readFile: ") || !strings.Contains(err.Error(), missing) {
+		t.Errorf("expected the line, the call and the path but got %v", err)
+	}
+
+	_, err = run(t, "path.join(1);")
+	if err == nil || !strings.HasPrefix(err.Error(), "[line 1] path.join") {
+		t.Errorf("expected the line and the call but got %v", err)
+	}
+}
+
+func TestFileSystemDisabled(t *testing.T) {
+	program, err := lang.Compile(`fs.exists("/");`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execution := interpreter.NewExecution(program)
+	execution.Host.FileSystem = false
+	if err := program.Execute(execution); err == nil || !strings.Contains(err.Error(), "disabled by the host") {
+		t.Errorf("expected fs to be disabled but got %v", err)
+	}
+}
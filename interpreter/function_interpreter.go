@@ -5,17 +5,37 @@ import (
 
 	"github.com/Atul-Ranjan12/environment"
 	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
 )
 
 // This file handles interpratation of functions
 
 type Callable interface {
-	// This will have an arity
-	// number of arguments of a function
-	Arity() int
+	// The arity is the range of the number of
+	// arguments of a function, MaxArity is
+	// VARIADIC when there is no upper limit
+	MinArity() int
+	MaxArity() int
 	Call(interpreter *Interpreter, arguments []interface{}) (interface{}, error)
 }
 
+// VARIADIC is the maximum arity of callables that accept
+// any number of arguments
+const VARIADIC int = -1
+
+// Parameterized is implemented by callables with named
+// parameters, which can be passed as keyword arguments
+type Parameterized interface {
+	ParameterNames() []string
+}
+
+// missingArgument marks a parameter without an argument in
+// a call with keyword arguments, the parameter takes its
+// default value
+type missingArgument struct{}
+
+var missing = &missingArgument{}
+
 // Type for the Return statements
 // It is treated as a runtime exception like break
 type ReturnValue struct {
@@ -32,15 +52,100 @@ func (r *ReturnValue) Error() string {
 
 // The Call function handles function calls
 func (i *Interpreter) VisitCallExpr(expr *expressions.Call) (interface{}, error) {
+	function, arguments, err := i.PrepareCall(expr)
+	if err != nil || function == nil {
+		return nil, err
+	}
+
+	i.Frames = append(i.Frames, Frame{Name: callableName(function), Line: expr.Paren.Line})
+	defer func() { i.Frames = i.Frames[:len(i.Frames)-1] }()
+	return function.Call(i, arguments)
+}
+
+// callableName gives the name of a callable for a frame
+func callableName(function Callable) string {
+	switch f := function.(type) {
+	case *Function:
+		return f.Declaration.Name.Lexeme
+	case *NativeFunction:
+		return f.Name
+	case *Class:
+		return f.Name
+	}
+	return fmt.Sprintf("%v", function)
+}
+
+// PrepareCall evaluates the callee and the arguments of a call
+// and checks them. The callee is nil when an optional call
+// short circuits
+func (i *Interpreter) PrepareCall(expr *expressions.Call) (Callable, []interface{}, error) {
 	// Evaluate the callee
 	// log.Println("This is called second")
-	callee, err := i.Evaluate(expr.Callee)
+	var callee interface{}
+	var err error
+	if get, ok := expr.Callee.(*expressions.Get); ok && get.Optional {
+		// An optional method call obj?.method() short circuits
+		// to nil before evaluating the arguments
+		var object interface{}
+		object, err = i.Evaluate(get.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		if object == nil {
+			return nil, nil, nil
+		}
+		callee, err = i.GetProperty(object, &get.Name)
+	} else {
+		callee, err = i.Evaluate(expr.Callee)
+	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	arguments, err := i.EvaluateArguments(expr.Arguments)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var arguments []interface{}
-	for _, argument := range expr.Arguments {
+	// See if the callee can be a function
+	// "Not a function"() is not a function
+	function, ok := callee.(Callable)
+	if !ok {
+		return nil, nil, i.RuntimeError(expr.Paren, "Can only call functions and classes")
+	}
+
+	if len(expr.Keywords) > 0 {
+		arguments, err = i.PlaceKeywordArguments(expr, function, arguments)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := i.CheckArity(expr.Paren, function, len(arguments), len(expr.Keywords) > 0); err != nil {
+		return nil, nil, err
+	}
+
+	return function, arguments, nil
+}
+
+// EvaluateArguments evaluates a list of expressions, a spread
+// expression is replaced by the elements of its list
+func (i *Interpreter) EvaluateArguments(exprs []expressions.Expr) ([]interface{}, error) {
+	arguments := make([]interface{}, 0, len(exprs))
+	for _, argument := range exprs {
+		if spread, ok := argument.(*expressions.Spread); ok {
+			value, err := i.Evaluate(spread.Expression)
+			if err != nil {
+				return nil, err
+			}
+			list, ok := value.(*List)
+			if !ok {
+				return nil, i.RuntimeError(spread.Ellipsis, "Can only spread a list, got "+i.stringify(value))
+			}
+			arguments = append(arguments, list.Elements...)
+			continue
+		}
+
 		arg, err := i.Evaluate(argument)
 		if err != nil {
 			return nil, err
@@ -49,19 +154,69 @@ func (i *Interpreter) VisitCallExpr(expr *expressions.Call) (interface{}, error)
 		// Append to arguments
 		arguments = append(arguments, arg)
 	}
+	return arguments, nil
+}
 
-	// See if the callee can be a function
-	// "Not a function"() is not a function
-	function, ok := callee.(Callable)
+// PlaceKeywordArguments puts keyword arguments at the position
+// of their parameter, parameters left without an argument are
+// marked as missing
+func (i *Interpreter) PlaceKeywordArguments(expr *expressions.Call, function Callable, arguments []interface{}) ([]interface{}, error) {
+	parameterized, ok := function.(Parameterized)
 	if !ok {
-		return nil, i.RuntimeError(expr.Paren, "Can only call functions and classes")
+		return nil, i.RuntimeError(expr.Keywords[0], "Callable does not accept keyword arguments")
 	}
+	names := parameterized.ParameterNames()
+
+	for index, keyword := range expr.Keywords {
+		position := -1
+		for p, name := range names {
+			if name == keyword.Lexeme {
+				position = p
+				break
+			}
+		}
+		if position < 0 {
+			return nil, i.RuntimeError(keyword, "Unknown keyword argument "+keyword.Lexeme)
+		}
+		if position < len(arguments) && arguments[position] != missing {
+			return nil, i.RuntimeError(keyword, "Argument "+keyword.Lexeme+" is given more than once")
+		}
 
-	if len(arguments) != function.Arity() {
-		return nil, i.RuntimeError(expr.Paren, fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(arguments)))
+		value, err := i.Evaluate(expr.KeywordValues[index])
+		if err != nil {
+			return nil, err
+		}
+		for len(arguments) <= position {
+			arguments = append(arguments, missing)
+		}
+		arguments[position] = value
 	}
 
-	return function.Call(i, arguments)
+	return arguments, nil
+}
+
+// CheckArity checks the number of arguments of a call. With
+// keyword arguments missing parameters are reported when the
+// arguments are bound instead
+func (i *Interpreter) CheckArity(paren token.Token, function Callable, count int, keywords bool) error {
+	min, max := function.MinArity(), function.MaxArity()
+	if min == max && count != min && !keywords {
+		return i.RuntimeError(paren, fmt.Sprintf("Expected %d arguments but got %d.", min, count))
+	}
+	if count < min && !keywords {
+		return i.RuntimeError(paren, fmt.Sprintf("Expected at least %d arguments but got %d.", min, count))
+	}
+	if max != VARIADIC && count > max {
+		return i.RuntimeError(paren, fmt.Sprintf("Expected at most %d arguments but got %d.", max, count))
+	}
+	return nil
+}
+
+// accepts checks if a function can be called with a number of
+// arguments, for natives calling a function they are given
+func accepts(function Callable, count int) bool {
+	max := function.MaxArity()
+	return count >= function.MinArity() && (max == VARIADIC || count <= max)
 }
 
 // Function is the structure for a function
@@ -81,11 +236,43 @@ func NewFunction(declaration *expressions.Function, closure *environment.Environ
 	}
 }
 
-// Arity returns the number of arguments
-func (f *Function) Arity() int {
+// MinArity returns the number of parameters without a default
+func (f *Function) MinArity() int {
+	min := 0
+	for index := range f.Declaration.Params {
+		if f.Declaration.Defaults[index] == nil && !f.IsRest(index) {
+			min++
+		}
+	}
+	return min
+}
+
+// MaxArity returns the number of parameters, or VARIADIC when
+// the last parameter collects the remaining arguments
+func (f *Function) MaxArity() int {
+	if f.Declaration.Variadic {
+		return VARIADIC
+	}
 	return len(f.Declaration.Params)
 }
 
+// IsRest checks if a parameter collects the remaining arguments
+func (f *Function) IsRest(index int) bool {
+	return f.Declaration.Variadic && index == len(f.Declaration.Params)-1
+}
+
+// ParameterNames returns the names of the parameters that can
+// be passed as keyword arguments
+func (f *Function) ParameterNames() []string {
+	var names []string
+	for index, param := range f.Declaration.Params {
+		if !f.IsRest(index) {
+			names = append(names, param.Lexeme)
+		}
+	}
+	return names
+}
+
 // ToString returns the function as what it is
 func (f *Function) ToString() string {
 	return "<fn " + f.Declaration.Name.Lexeme + " >"
@@ -107,16 +294,51 @@ func (f *Function) Call(i *Interpreter, arguments []interface{}) (interface{}, e
 	environment := environment.NewEnvironment(f.Closure)
 
 	// Bind arguments to parameters
-	for i, param := range f.Declaration.Params {
-		if i < len(arguments) {
-			environment.Define(param.Lexeme, arguments[i])
-		} else {
-			// Handle case where fewer arguments are provided than parameters
-			environment.Define(param.Lexeme, nil)
+	for index, param := range f.Declaration.Params {
+		if f.IsRest(index) {
+			// Collect the remaining arguments in a list
+			rest := []interface{}{}
+			if index < len(arguments) {
+				rest = append(rest, arguments[index:]...)
+			}
+			environment.Define(param.Lexeme, NewList(rest))
+			break
+		}
+
+		if index < len(arguments) && arguments[index] != missing {
+			environment.Define(param.Lexeme, arguments[index])
+			continue
+		}
+
+		defaultValue := f.Declaration.Defaults[index]
+		if defaultValue == nil {
+			return nil, fmt.Errorf("Missing argument for parameter %s of %s", param.Lexeme, f.Declaration.Name.Lexeme)
+		}
+
+		// Defaults are evaluated at every call and can use
+		// the parameters before them
+		value, err := i.EvaluateIn(defaultValue, environment)
+		if err != nil {
+			return nil, err
 		}
+		environment.Define(param.Lexeme, value)
 	}
 
-	// Execute the function body
+	// A generator only runs its body when asked for values
+	if f.Declaration.Generator {
+		return NewGenerator(i, f, environment), nil
+	}
+	// An async function runs its body on the event loop
+	if f.Declaration.Async {
+		return i.StartAsync(f, environment), nil
+	}
+
+	return f.Run(i, environment)
+}
+
+// Run executes the body of the function in the environment
+// holding its arguments
+func (f *Function) Run(i *Interpreter, environment *environment.Environment) (interface{}, error) {
 	err := i.ExecuteBlock(f.Declaration.Body, environment)
 	if err != nil {
 		// Check if the error is actually a return statement
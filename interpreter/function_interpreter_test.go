@@ -0,0 +1,138 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOptionalCallErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`def f(s) { return s?.nope(); } f("abc");`, "Strings have no method nope"},
+		{`
+struct Box {
+  construct() {}
+  get open() { return [][1]; }
+}
+Box()?.open();
+`, "out of range"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestOptionalCall(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{`nil?.upper()`, nil},
+		{`"abc"?.upper()`, "ABC"},
+		{`nil?.upper() ?? "none"`, "none"},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestParameterKinds(t *testing.T) {
+	execution, err := run(t, `
+def describe(a, b = a * 2, ...rest) {
+  return "{} {} {}".format(a, b, rest);
+}
+def counter(start = 0, step = 1) { return start + step; }
+var defaults = describe(1);
+var explicit = describe(1, 5);
+var rest = describe(1, 2, 3, 4);
+var keywords = describe(b: 3, a: 1);
+var mixed = describe(1, b: 7);
+var skipped = counter(step: 5);
+var xs = [1, 2, 3];
+var spread = describe(...xs);
+var spreadTail = describe(0, ...xs, 9);
+var spreadEmpty = describe(1, ...[]);
+def total(...numbers) {
+  var sum = 0;
+  for (var n in numbers) { sum = sum + n; }
+  return sum;
+}
+var none = total();
+var many = total(1, 2, 3, 4);
+var native = math.max(3, 9, 4);
+var nativeSpread = math.max(...[3, 9, 4]);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"defaults", "1 2 []"},
+		{"explicit", "1 5 []"},
+		{"rest", "1 2 [3, 4]"},
+		{"keywords", "1 3 []"},
+		{"mixed", "1 7 []"},
+		{"skipped", int64(5)},
+		{"spread", "1 2 [3]"},
+		{"spreadTail", "0 1 [2, 3, 9]"},
+		{"spreadEmpty", "1 2 []"},
+		{"none", int64(0)},
+		{"many", int64(10)},
+		{"native", int64(9)},
+		{"nativeSpread", int64(9)},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDefaultsAreEvaluatedAtEveryCall(t *testing.T) {
+	execution, err := run(t, `
+var calls = 0;
+def next() { calls = calls + 1; return calls; }
+def f(x = next()) { return x; }
+var first = f();
+var given = f(10);
+var second = f();
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "second"); got != int64(2) {
+		t.Errorf("got second %v, want 2", got)
+	}
+}
+
+func TestParameterKindErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`def f(a, b) {} [f][0](1);`, "Expected 2 arguments but got 1."},
+		{`def f(a, b = 1) {} [f][0]();`, "Expected at least 1 arguments but got 0."},
+		{`def f(a, b = 1) {} [f][0](1, 2, 3);`, "Expected at most 2 arguments but got 3."},
+		{`def f(a, b = 1) {} [f][0](b: 2);`, "Missing argument for parameter a of f"},
+		{`def f(a) {} [f][0](c: 2);`, "Unknown keyword argument c"},
+		{`def f(a) {} [f][0](1, a: 2);`, "Argument a is given more than once"},
+		{`def f(...rest) {} [f][0](rest: 1);`, "Unknown keyword argument rest"},
+		{`def f(a) {} [f][0](...[1][0]);`, "Can only spread a list, got 1"},
+		{`[len][0](x: 1);`, "Callable does not accept keyword arguments"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package interpreter
+
+import (
+	"errors"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Atul-Ranjan12/environment"
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles generators, the iterators returned by
+// functions containing yield. A generator only runs its body as
+// values are asked for with next(), which gives nil once it has
+// finished, or a for in loop
+
+// errGeneratorClosed unwinds the body of a generator that is
+// closed before it finished
+var errGeneratorClosed = errors.New("Generator closed")
+
+// generatorStep is a value handed from the body of a generator
+// to its caller, done is set when the body has finished
+type generatorStep struct {
+	value interface{}
+	err   error
+	done  bool
+}
+
+// Generator represents a generator in runtime. Its body runs on
+// its own goroutine and interpreter, handing control back and
+// forth with the caller so only one of them runs at a time. A
+// generator abandoned before it finished is closed when the
+// program ends
+type Generator struct {
+	Function    *Function
+	Environment *environment.Environment
+	Interpreter *Interpreter
+
+	// resume continues the body after a yield, false stops it
+	resume chan bool
+	yields chan generatorStep
+
+	// mutex guards the state, a generator can not be
+	// resumed while it is running
+	mutex   sync.Mutex
+	started bool
+	running bool
+	done    bool
+}
+
+// NewGenerator creates a generator running the body of a
+// function in the environment holding its arguments
+func NewGenerator(i *Interpreter, function *Function, env *environment.Environment) *Generator {
+	g := &Generator{
+		Function:    function,
+		Environment: env,
+		Interpreter: i.Fork(),
+		resume:      make(chan bool),
+		yields:      make(chan generatorStep),
+	}
+	g.Interpreter.Generator = g
+	return g
+}
+
+// Generators holds the generators of an execution that started
+// but have not finished, so their goroutines are stopped when
+// the program ends
+type Generators struct {
+	mutex   sync.Mutex
+	started map[*Generator]bool
+}
+
+// NewGenerators creates an empty set of generators
+func NewGenerators() *Generators {
+	return &Generators{started: make(map[*Generator]bool)}
+}
+
+func (s *Generators) add(g *Generator) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.started[g] = true
+}
+
+func (s *Generators) remove(g *Generator) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.started, g)
+}
+
+// CloseAll closes every generator that has not finished. A
+// generator still running on a task is left alone
+func (s *Generators) CloseAll() {
+	s.mutex.Lock()
+	generators := make([]*Generator, 0, len(s.started))
+	for g := range s.started {
+		generators = append(generators, g)
+	}
+	s.mutex.Unlock()
+
+	for _, g := range generators {
+		g.Close()
+	}
+}
+
+func (g *Generator) String() string {
+	return "<generator " + g.Function.Declaration.Name.Lexeme + ">"
+}
+
+// run executes the body of the generator on its goroutine
+func (g *Generator) run() {
+	err := g.Interpreter.ExecuteBlock(g.Function.Declaration.Body, g.Environment)
+	if _, ok := err.(*ReturnValue); ok || err == errGeneratorClosed {
+		err = nil
+	}
+	g.yields <- generatorStep{err: err, done: true}
+}
+
+// Next runs the generator until its next yield, ok is false
+// once the generator has finished
+func (g *Generator) Next() (value interface{}, ok bool, err error) {
+	if err := g.acquire(); err != nil {
+		return nil, false, err
+	}
+	defer g.release()
+	if g.done {
+		return nil, false, nil
+	}
+
+	step := g.switchTo(true)
+	if step.done {
+		g.done = true
+		g.Interpreter.Generators.remove(g)
+	}
+	return step.value, !step.done, step.err
+}
+
+// Close stops a generator that has not finished, its body is
+// unwound without running any further statements
+func (g *Generator) Close() error {
+	if err := g.acquire(); err != nil {
+		return err
+	}
+	defer g.release()
+	if g.started && !g.done {
+		g.switchTo(false)
+		g.Interpreter.Generators.remove(g)
+	}
+	g.done = true
+	return nil
+}
+
+// acquire marks the generator as running, an error is returned
+// when it already is
+func (g *Generator) acquire() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.running {
+		return errors.New("Generator " + g.Function.Declaration.Name.Lexeme + " is already running")
+	}
+	g.running = true
+	return nil
+}
+
+func (g *Generator) release() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.running = false
+}
+
+// switchTo hands control to the body of the generator and
+// waits for it to yield or finish
+func (g *Generator) switchTo(resume bool) generatorStep {
+	if !g.started {
+		g.started = true
+		g.Interpreter.Generators.add(g)
+		go g.run()
+	} else {
+		g.resume <- resume
+	}
+	return <-g.yields
+}
+
+// VisitYieldStmt hands a value to the caller of the generator
+// and waits until the generator is resumed
+func (i *Interpreter) VisitYieldStmt(stmt *expressions.Yield) (interface{}, error) {
+	var value interface{}
+	var err error
+	if stmt.Value != nil {
+		value, err = i.Evaluate(stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	generator := i.Generator
+	if generator == nil {
+		return nil, i.RuntimeError(stmt.Keyword, "Can only yield inside a generator")
+	}
+	generator.yields <- generatorStep{value: value}
+	if !<-generator.resume {
+		return nil, errGeneratorClosed
+	}
+	return nil, nil
+}
+
+// GetGeneratorMethod returns the method of a generator bound to it
+func (i *Interpreter) GetGeneratorMethod(g *Generator, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "next":
+		// next gives nil once the generator has finished
+		return NewNativeFunction("next", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			value, _, err := g.Next()
+			return value, err
+		}), nil
+	case "close":
+		return NewNativeFunction("close", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return nil, g.Close()
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Generators have no method "+name.Lexeme)
+}
+
+// VisitForInStmt runs the body of a loop for every value of a
+// list, key of a map, character of a string or value of a
+// generator or channel, each in a new environment holding the
+// loop variable
+func (i *Interpreter) VisitForInStmt(stmt *expressions.ForIn) (interface{}, error) {
+	iterable, err := i.Evaluate(stmt.Iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := i.Iterator(stmt.Keyword, iterable)
+	if err != nil {
+		return nil, err
+	}
+	generator, _ := iterable.(*Generator)
+
+	for {
+		value, ok, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+
+		env := environment.NewEnvironment(i.Environment)
+		env.Define(stmt.Name.Lexeme, value)
+		err = i.ExecuteBlock([]expressions.Stmt{stmt.Body}, env)
+		if err != nil {
+			// A generator left early is closed
+			if generator != nil {
+				generator.Close()
+			}
+			if err.Error() == "CODE_999_LOOP_BREAK" {
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+// Iterator returns a function giving the values a loop iterates
+// over, ok is false once there are no more values
+func (i *Interpreter) Iterator(keyword token.Token, iterable interface{}) (func() (interface{}, bool, error), error) {
+	index := 0
+	switch v := iterable.(type) {
+	case *Generator:
+		return func() (interface{}, bool, error) {
+			return v.Next()
+		}, nil
+	case *Channel:
+		// A channel is received from until it is closed
+		return func() (interface{}, bool, error) {
+			return v.Recv(i.Loop)
+		}, nil
+	case *List:
+		// The length is read on every step so elements
+		// appended by the body are visited as well
+		return func() (interface{}, bool, error) {
+			if index >= len(v.Elements) {
+				return nil, false, nil
+			}
+			index++
+			return v.Elements[index-1], true, nil
+		}, nil
+	case *Map:
+		keys := make([]interface{}, len(v.Keys))
+		copy(keys, v.Keys)
+		return func() (interface{}, bool, error) {
+			if index >= len(keys) {
+				return nil, false, nil
+			}
+			index++
+			return keys[index-1], true, nil
+		}, nil
+	case string:
+		return func() (interface{}, bool, error) {
+			if index >= len(v) {
+				return nil, false, nil
+			}
+			r, size := utf8.DecodeRuneInString(v[index:])
+			index += size
+			return string(r), true, nil
+		}, nil
+	}
+	return nil, i.RuntimeError(keyword, "Can only iterate over lists, maps, strings, generators and channels, got "+i.stringify(iterable))
+}
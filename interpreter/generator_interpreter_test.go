@@ -0,0 +1,65 @@
+package interpreter_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Atul-Ranjan12/lang"
+)
+
+func TestUnfinishedGeneratorsAreClosed(t *testing.T) {
+	program, err := lang.Compile("def g() { yield 1; yield 2; } var it = g(); it.next();")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := runtime.NumGoroutine()
+	for run := 0; run < 1000; run++ {
+		if err := program.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The goroutines of closed generators end shortly after
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+10 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if count := runtime.NumGoroutine(); count > before+10 {
+		t.Errorf("%d goroutines are left from %d before", count, before)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	source := `
+def count(n) {
+  var i = 0;
+  while (i < n) {
+    yield i;
+    i = i + 1;
+  }
+}
+var total = 0;
+for (var x in count(5)) {
+  total = total + x;
+}
+var it = count(10);
+it.next();
+var second = it.next();
+it.close();
+var closed = it.next();
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := global(t, execution, "total"); total != int64(10) {
+		t.Errorf("got total %v", total)
+	}
+	if second := global(t, execution, "second"); second != int64(1) {
+		t.Errorf("got second %v", second)
+	}
+	if closed := global(t, execution, "closed"); closed != nil {
+		t.Errorf("got %v after close", closed)
+	}
+}
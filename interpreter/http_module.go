@@ -0,0 +1,385 @@
+package interpreter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles the http module, a client giving promises
+// of responses and a server calling a handler for every request
+// on the event loop. A response is a map of its status, headers
+// and body, a body that is not a string is sent as JSON. The
+// handler gets a map of the method, path, query, headers and
+// body of a request and returns the body, a response map or a
+// promise of either, an error in it is a response with status
+// 500. A server keeps the program running until it is closed
+
+// DefineHTTPModule defines the http module
+func (i *Interpreter) DefineHTTPModule() {
+	module := NewModule("http")
+	module.Function("get", 1, 2, httpGet)
+	module.Function("post", 2, 3, httpPost)
+	module.Function("request", 1, 1, httpRequest)
+	module.Function("serve", 2, 2, httpServe)
+	i.Globals.Define(module.Name, module)
+}
+
+// httpOptions describes a request made by the client
+type httpOptions struct {
+	method  string
+	url     string
+	headers http.Header
+	body    []byte
+	timeout time.Duration
+}
+
+// httpGet gives a promise of the response to a GET request, the
+// second argument is a map of headers
+func httpGet(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckNetwork("http.get"); err != nil {
+		return nil, err
+	}
+	url, err := stringArg("http.get", args[0])
+	if err != nil {
+		return nil, err
+	}
+	options := &httpOptions{method: http.MethodGet, url: url, headers: make(http.Header)}
+	if len(args) == 2 {
+		if err := httpHeaders(i, "http.get", args[1], options.headers); err != nil {
+			return nil, err
+		}
+	}
+	return i.Background("http.get", options.send), nil
+}
+
+// httpPost gives a promise of the response to a POST request, a
+// body that is not a string is sent as JSON
+func httpPost(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckNetwork("http.post"); err != nil {
+		return nil, err
+	}
+	url, err := stringArg("http.post", args[0])
+	if err != nil {
+		return nil, err
+	}
+	options := &httpOptions{method: http.MethodPost, url: url, headers: make(http.Header)}
+	if len(args) == 3 {
+		if err := httpHeaders(i, "http.post", args[2], options.headers); err != nil {
+			return nil, err
+		}
+	}
+	if options.body, err = httpBody(i, "http.post", args[1], options.headers); err != nil {
+		return nil, err
+	}
+	return i.Background("http.post", options.send), nil
+}
+
+// httpRequest gives a promise of the response to a request
+// described by a map of its method (GET by default), url,
+// headers, body and timeout in milliseconds
+func httpRequest(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckNetwork("http.request"); err != nil {
+		return nil, err
+	}
+	request, ok := args[0].(*Map)
+	if !ok {
+		return nil, errors.New("http.request expects a map but got " + i.stringify(args[0]))
+	}
+	options := &httpOptions{method: http.MethodGet, headers: make(http.Header)}
+
+	for _, key := range request.Keys {
+		value := request.Values[key]
+		switch key {
+		case "method":
+			method, err := stringArg("http.request", value)
+			if err != nil {
+				return nil, err
+			}
+			options.method = strings.ToUpper(method)
+		case "url":
+			url, err := stringArg("http.request", value)
+			if err != nil {
+				return nil, err
+			}
+			options.url = url
+		case "headers":
+			if err := httpHeaders(i, "http.request", value, options.headers); err != nil {
+				return nil, err
+			}
+		case "timeout":
+			timeout, err := durationArg(i, "http.request", value)
+			if err != nil {
+				return nil, err
+			}
+			options.timeout = timeout
+		case "body":
+		default:
+			return nil, errors.New("http.request has no option " + i.stringify(key))
+		}
+	}
+	if options.url == "" {
+		return nil, errors.New("http.request expects a url")
+	}
+	// The body is read last so a content type in the headers
+	// is not replaced
+	if body, ok := request.Values["body"]; ok {
+		var err error
+		if options.body, err = httpBody(i, "http.request", body, options.headers); err != nil {
+			return nil, err
+		}
+	}
+	return i.Background("http.request", options.send), nil
+}
+
+// send makes the request and converts the response to a map of
+// its status, headers and body
+func (o *httpOptions) send() (interface{}, error) {
+	request, err := http.NewRequest(o.method, o.url, bytes.NewReader(o.body))
+	if err != nil {
+		return nil, errors.New("http: " + err.Error())
+	}
+	request.Header = o.headers
+
+	client := &http.Client{Timeout: o.timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, errors.New("http: " + err.Error())
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.New("http: " + err.Error())
+	}
+
+	result := NewMap()
+	result.Set("status", int64(response.StatusCode))
+	result.Set("headers", headerMap(response.Header))
+	result.Set("body", string(body))
+	return result, nil
+}
+
+// httpHeaders adds the entries of a map of headers to a header
+func httpHeaders(i *Interpreter, function string, value interface{}, header http.Header) error {
+	headers, ok := value.(*Map)
+	if !ok {
+		return errors.New(function + " expects a map of headers but got " + i.stringify(value))
+	}
+	for _, key := range headers.Keys {
+		name, ok := key.(string)
+		if !ok {
+			return errors.New(function + " expects header names that are strings but got " + i.stringify(key))
+		}
+		header.Set(name, i.stringify(headers.Values[key]))
+	}
+	return nil
+}
+
+// httpBody converts a body to bytes, a body that is not a string
+// is encoded as JSON
+func httpBody(i *Interpreter, function string, value interface{}, header http.Header) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	}
+
+	encoder := &jsonEncoder{interpreter: i, visiting: make(map[interface{}]bool)}
+	if err := encoder.encode(value); err != nil {
+		return nil, errors.New(function + ": " + err.Error())
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+	return encoder.buffer.Bytes(), nil
+}
+
+// headerMap converts a header to a map, the values of a header
+// given more than once are joined by commas
+func headerMap(header http.Header) *Map {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := NewMap()
+	for _, name := range names {
+		headers.Set(name, strings.Join(header[name], ", "))
+	}
+	return headers
+}
+
+// Server is an HTTP server started with http.serve, it keeps the
+// event loop running until it is closed
+type Server struct {
+	Address string
+	server  *http.Server
+}
+
+func (s *Server) String() string {
+	return "<server " + s.Address + ">"
+}
+
+// httpServe starts serving on an address, calling the handler
+// with a map of the method, path, query, headers and body of
+// every request. The handler returns the body or a map of the
+// status, headers and body of the response, an async handler
+// gives a promise of it
+func httpServe(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckNetwork("http.serve"); err != nil {
+		return nil, err
+	}
+	address, err := stringArg("http.serve", args[0])
+	if err != nil {
+		return nil, err
+	}
+	handler, ok := args[1].(Callable)
+	if !ok {
+		return nil, errors.New("http.serve expects a handler function but got " + i.stringify(args[1]))
+	}
+	if !accepts(handler, 1) {
+		return nil, errors.New("http.serve expects a handler of one parameter but got " + callableName(handler))
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, errors.New("http.serve: " + err.Error())
+	}
+	server := &Server{
+		Address: listener.Addr().String(),
+		server:  &http.Server{Handler: i.HTTPHandler(handler)},
+	}
+
+	// A server that stops on its own rejects a promise
+	// nothing can await, so the error is reported at exit
+	stopped := NewPromise("http.serve", i.Trace())
+	i.Loop.Begin()
+	go func() {
+		err := server.server.Serve(listener)
+		if err == http.ErrServerClosed {
+			err = nil
+		} else {
+			err = errors.New("http.serve: " + err.Error())
+		}
+		i.Loop.Finish(func() { stopped.Settle(i.Loop, nil, err) })
+	}()
+	return server, nil
+}
+
+// httpResult is the result of a handler for a request
+type httpResult struct {
+	value interface{}
+	err   error
+}
+
+// HTTPHandler creates an http.Handler calling a handler of the
+// script on the event loop for every request, so a host can
+// serve it on a server of its own
+func (i *Interpreter) HTTPHandler(handler Callable) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query := NewMap()
+		for name, values := range request.URL.Query() {
+			query.Set(name, values[0])
+		}
+		arg := NewMap()
+		arg.Set("method", request.Method)
+		arg.Set("path", request.URL.Path)
+		arg.Set("query", query)
+		arg.Set("headers", headerMap(request.Header))
+		arg.Set("body", string(body))
+
+		results := make(chan httpResult, 1)
+		i.Loop.Schedule(func() {
+			// A host passes the handler without http.serve
+			// checking it
+			if !accepts(handler, 1) {
+				results <- httpResult{err: errors.New("The handler must have one parameter, " + callableName(handler) + " can not be called with a request")}
+				return
+			}
+			value, err := handler.Call(i.Fork(), []interface{}{arg})
+			promise, ok := value.(*Promise)
+			if err != nil || !ok {
+				results <- httpResult{value, err}
+				return
+			}
+			promise.OnSettle(i.Loop, func() {
+				value, err := promise.Result()
+				results <- httpResult{value, err}
+			})
+		})
+
+		select {
+		case result := <-results:
+			i.writeResponse(writer, result)
+		case <-request.Context().Done():
+		}
+	})
+}
+
+// writeResponse writes the result of a handler, an error in the
+// handler is a response with status 500
+func (i *Interpreter) writeResponse(writer http.ResponseWriter, result httpResult) {
+	if signal, ok := result.err.(*ExitSignal); ok {
+		i.Loop.Exit(signal)
+	}
+	if result.err != nil {
+		http.Error(writer, result.err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	var body interface{} = result.value
+	if response, ok := result.value.(*Map); ok {
+		body = response.Values["body"]
+		if value, ok := response.Values["status"]; ok {
+			code, ok := value.(int64)
+			if !ok || code < 100 || code > 999 {
+				http.Error(writer, "The status of a response must be an integer from 100 to 999, got "+i.stringify(value), http.StatusInternalServerError)
+				return
+			}
+			status = int(code)
+		}
+		if headers, ok := response.Values["headers"]; ok {
+			if err := httpHeaders(i, "The handler", headers, writer.Header()); err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	data, err := httpBody(i, "The handler", body, writer.Header())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(status)
+	writer.Write(data)
+}
+
+// GetServerMethod returns a property of a server, its address
+// or its close method
+func (i *Interpreter) GetServerMethod(s *Server, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "address":
+		return s.Address, nil
+	case "close":
+		return NewNativeFunction("close", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return nil, s.server.Close()
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Servers have no property "+name.Lexeme)
+}
@@ -0,0 +1,213 @@
+package interpreter_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/lang"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// global gives the value of a global of an execution
+func global(t *testing.T, execution *interpreter.Interpreter, name string) interface{} {
+	t.Helper()
+	value, err := execution.Globals.Get(nameToken(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+// nameToken creates the token of an identifier
+func nameToken(name string) *token.Token {
+	return &token.Token{Type: token.IDENTIFIER, Lexeme: name}
+}
+
+// field gives the value of a key of a map
+func field(t *testing.T, value interface{}, key string) interface{} {
+	t.Helper()
+	m, ok := value.(*interpreter.Map)
+	if !ok {
+		t.Fatalf("expected a map but got %#v", value)
+	}
+	return m.Values[key]
+}
+
+func TestHTTPGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		w.WriteHeader(http.StatusTeapot)
+		io.WriteString(w, "hello "+r.Header.Get("X-Name"))
+	}))
+	defer server.Close()
+
+	execution, err := run(t, `var response = await http.get("`+server.URL+`/greet", {"X-Name": "lang"});`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := global(t, execution, "response")
+	if status := field(t, response, "status"); status != int64(http.StatusTeapot) {
+		t.Errorf("got status %v", status)
+	}
+	if body := field(t, response, "body"); body != "hello lang" {
+		t.Errorf("got body %v", body)
+	}
+	if path := field(t, field(t, response, "headers"), "X-Path"); path != "/greet" {
+		t.Errorf("got the header %v", path)
+	}
+}
+
+func TestHTTPPostEncodesJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, r.Method+" "+r.Header.Get("Content-Type")+" "+body["name"].(string))
+	}))
+	defer server.Close()
+
+	execution, err := run(t, `var response = await http.post("`+server.URL+`", {"name": "lang", "size": 3});`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := field(t, global(t, execution, "response"), "body"); body != "POST application/json lang" {
+		t.Errorf("got body %v", body)
+	}
+}
+
+func TestHTTPRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	_, err := run(t, `await http.request({"url": "`+server.URL+`", "timeout": 20});`)
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("expected a timeout but got %v", err)
+	}
+}
+
+func TestHTTPRequestUnknownOption(t *testing.T) {
+	_, err := run(t, `http.request({"url": "http://localhost", "retries": 3});`)
+	if err == nil || !strings.Contains(err.Error(), "has no option") {
+		t.Errorf("expected an unknown option but got %v", err)
+	}
+}
+
+func TestHTTPServe(t *testing.T) {
+	source := `
+def handle(request) {
+  if (request["path"] == "/missing") {
+    return {"status": 404, "body": "no " + request["query"]["name"]};
+  }
+  return {"body": {"method": request["method"], "body": request["body"]}};
+}
+var server = http.serve("127.0.0.1:0", handle);
+var found = await http.post("http://" + server.address + "/", "data");
+var missing = await http.get("http://" + server.address + "/missing?name=x");
+server.close();
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := global(t, execution, "found")
+	if body := field(t, found, "body"); body != `{"method":"POST","body":"data"}` {
+		t.Errorf("got body %v", body)
+	}
+	missing := global(t, execution, "missing")
+	if status := field(t, missing, "status"); status != int64(404) {
+		t.Errorf("got status %v", status)
+	}
+	if body := field(t, missing, "body"); body != "no x" {
+		t.Errorf("got body %v", body)
+	}
+}
+
+func TestHTTPServeHandlerArity(t *testing.T) {
+	_, err := run(t, `http.serve("127.0.0.1:0", implements);`)
+	if err == nil || !strings.Contains(err.Error(), "one parameter") {
+		t.Errorf("expected an arity error but got %v", err)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	source := `
+var count = 0;
+async def handle(request) {
+  await sleep(1);
+  count = count + 1;
+  if (request["path"] == "/fail") {
+    return 1//0;
+  }
+  return "request " + "{}".format(count);
+}
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle := global(t, execution, "handle").(interpreter.Callable)
+
+	// The host keeps the event loop running while it serves
+	execution.Loop.Begin()
+	stopped := make(chan struct{})
+	go func() {
+		execution.Loop.Run()
+		close(stopped)
+	}()
+	server := httptest.NewServer(execution.HTTPHandler(handle))
+
+	for _, want := range []string{"request 1", "request 2"} {
+		response, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(response.Body)
+		response.Body.Close()
+		if string(body) != want {
+			t.Errorf("got %q, want %q", body, want)
+		}
+	}
+
+	response, err := http.Get(server.URL + "/fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("got status %d for a failing handler", response.StatusCode)
+	}
+
+	server.Close()
+	execution.Loop.Finish(func() {})
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event loop did not stop")
+	}
+}
+
+func TestHTTPDisabled(t *testing.T) {
+	for _, source := range []string{`http.get("http://localhost");`, `def handle(request) {} http.serve("127.0.0.1:0", handle);`} {
+		program, err := lang.Compile(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		execution := interpreter.NewExecution(program)
+		execution.Host.Network = false
+		if err := program.Execute(execution); err == nil || !strings.Contains(err.Error(), "disabled by the host") {
+			t.Errorf("%s: expected http to be disabled but got %v", source, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// VisitIndexExpr reads an element of a list, a map or a string,
+// an instance is indexed by its __index__ method
+func (i *Interpreter) VisitIndexExpr(expr *expressions.Index) (interface{}, error) {
+	object, err := i.Evaluate(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.Evaluate(expr.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.GetIndex(expr.Bracket, object, index)
+}
+
+// GetIndex reads an element of an evaluated object
+func (i *Interpreter) GetIndex(bracket token.Token, object, index interface{}) (interface{}, error) {
+	switch v := object.(type) {
+	case *List:
+		position, err := i.ElementIndex(bracket, index, v.Len())
+		if err != nil {
+			return nil, err
+		}
+		return v.Elements[position], nil
+	case *Map:
+		// A missing key reads as nil
+		value, _, err := v.Get(index)
+		if err != nil {
+			return nil, i.RuntimeError(bracket, err.Error())
+		}
+		return value, nil
+	case string:
+		runes := []rune(v)
+		position, err := i.ElementIndex(bracket, index, len(runes))
+		if err != nil {
+			return nil, err
+		}
+		return string(runes[position]), nil
+	case *Instance:
+		if _, ok := v.ClassName.Methods[INDEX_METHOD]; !ok {
+			return nil, i.RuntimeError(bracket, v.ClassName.Name+" does not define "+INDEX_METHOD)
+		}
+		return i.CallMethod(v, INDEX_METHOD, []interface{}{index})
+	}
+
+	return nil, i.RuntimeError(bracket, "Only lists, maps, strings and instances can be indexed")
+}
+
+// VisitSetIndexExpr sets an element of a list or a map, an
+// instance handles the assignment in its __setindex__ method
+func (i *Interpreter) VisitSetIndexExpr(expr *expressions.SetIndex) (interface{}, error) {
+	object, err := i.Evaluate(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.Evaluate(expr.Index)
+	if err != nil {
+		return nil, err
+	}
+	value, err := i.Evaluate(expr.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.SetIndex(expr.Bracket, object, index, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// SetIndex sets an element of an evaluated object
+func (i *Interpreter) SetIndex(bracket token.Token, object, index, value interface{}) error {
+	switch v := object.(type) {
+	case *List:
+		if v.IsFrozen() {
+			return i.RuntimeError(bracket, "Can not change a frozen list")
+		}
+		position, err := i.ElementIndex(bracket, index, v.Len())
+		if err != nil {
+			return err
+		}
+		v.Elements[position] = value
+		return nil
+	case *Map:
+		if err := v.Set(index, value); err != nil {
+			return i.RuntimeError(bracket, err.Error())
+		}
+		return nil
+	case *Instance:
+		if _, ok := v.ClassName.Methods[SET_INDEX_METHOD]; !ok {
+			return i.RuntimeError(bracket, v.ClassName.Name+" does not define "+SET_INDEX_METHOD)
+		}
+		_, err := i.CallMethod(v, SET_INDEX_METHOD, []interface{}{index, value})
+		return err
+	}
+
+	return i.RuntimeError(bracket, "Only lists, maps and instances can be assigned by index")
+}
+
+// ElementIndex checks an index into a sequence of the given
+// length, a negative index counts from the end
+func (i *Interpreter) ElementIndex(bracket token.Token, index interface{}, length int) (int, error) {
+	position, err := toIndex(index)
+	if err != nil {
+		return 0, i.RuntimeError(bracket, err.Error())
+	}
+	if position < -length || position >= length {
+		return 0, i.RuntimeError(bracket, fmt.Sprintf("Index %d is out of range for length %d", position, length))
+	}
+	if position < 0 {
+		position += length
+	}
+	return position, nil
+}
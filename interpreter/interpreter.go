@@ -3,7 +3,11 @@ package interpreter
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/Atul-Ranjan12/environment"
 	"github.com/Atul-Ranjan12/parser/expressions"
@@ -17,6 +21,58 @@ type Interpreter struct {
 	Globals     *environment.Environment
 	Environment *environment.Environment // Current environment
 	Locals      map[expressions.Expr]int
+	// Generator is the generator whose body is running, a
+	// yield hands its value to it
+	Generator *Generator
+	// Generators are the unfinished generators of every
+	// interpreter forked from the same interpreter
+	Generators *Generators
+	// Loop runs the async functions, timers and I/O of every
+	// interpreter forked from the same interpreter
+	Loop *EventLoop
+	// Context is the async function whose body is running, an
+	// await parks it until the promise settles
+	Context *asyncContext
+	// Frames are the calls being made, innermost last
+	Frames []Frame
+	// Host holds what the embedding program allows scripts
+	// to do, it is shared with every fork
+	Host *Host
+}
+
+// Host holds the permissions an embedding program gives its
+// scripts, everything is allowed unless the host disables it
+type Host struct {
+	// FileSystem allows the natives reading and writing files
+	FileSystem bool
+	// Process allows the natives reading and changing the
+	// environment variables and running other processes
+	Process bool
+	// Network allows the natives opening network connections
+	Network bool
+	// Args are the arguments given to the script
+	Args []string
+}
+
+// Frame is a call being made, kept to describe where an
+// async function was called from
+type Frame struct {
+	Name string
+	Line int
+}
+
+// Fork creates an interpreter for a task or a generator, sharing
+// the globals and resolved locals but with its own execution state
+func (i *Interpreter) Fork() *Interpreter {
+	return &Interpreter{
+		Globals:     i.Globals,
+		Environment: i.Globals,
+		Locals:      i.Locals,
+		Loop:        i.Loop,
+		Generators:  i.Generators,
+		Frames:      append([]Frame(nil), i.Frames...),
+		Host:        i.Host,
+	}
 }
 
 func (i *Interpreter) Define(env *environment.Environment, callable Callable, callableName string) {
@@ -25,6 +81,12 @@ func (i *Interpreter) Define(env *environment.Environment, callable Callable, ca
 
 // NewInterpreter is the initializer for ther interpreter
 func NewInterpreter() *Interpreter {
+	return newInterpreter(make(map[expressions.Expr]int))
+}
+
+// newInterpreter creates an interpreter with its own globals
+// looking up local variables in locals
+func newInterpreter(locals map[expressions.Expr]int) *Interpreter {
 	// Create new environment
 	globalEnvironment := environment.NewEnvironment(nil)
 	// If it does not exist on the interpreter environment
@@ -36,11 +98,25 @@ func NewInterpreter() *Interpreter {
 	i := &Interpreter{
 		Globals:     globalEnvironment,
 		Environment: globalEnvironment,
-		Locals:      make(map[expressions.Expr]int),
+		Locals:      locals,
+		Loop:        NewEventLoop(),
+		Generators:  NewGenerators(),
+		Host:        &Host{FileSystem: true, Process: true, Network: true},
 	}
 
 	// Define the native functions
 	i.Define(i.Globals, &Clock{}, "clock")
+	i.DefineStringFunctions()
+	i.DefineObjectFunctions()
+	i.DefineTaskFunctions()
+	i.DefineAsyncFunctions()
+	i.DefineMathModule()
+	i.DefineFileSystemModules()
+	i.DefineProcessFunctions()
+	i.DefineJSONModule()
+	i.DefineHTTPModule()
+	i.DefineTimeModule()
+	i.DefineReModule()
 
 	return i
 }
@@ -75,31 +151,132 @@ func (i *Interpreter) Evaluate(expr expressions.Expr) (interface{}, error) {
 	return expr.Accept(i)
 }
 
-// Execute is the method for all statements
+// Execute is the method for all statements, no statement runs
+// once a task has called exit
 func (i *Interpreter) Execute(expr expressions.Stmt) (interface{}, error) {
+	select {
+	case <-i.Loop.Stopped():
+		return nil, i.Loop.Exited()
+	default:
+	}
 	return expr.Accept(i)
 }
 
-// Interpret evaluates the expression and returns the result as a string
+// Interpret evaluates the expression and returns the result as a string.
+// The event loop runs once the statements are done, rejected
+// promises that were never awaited are returned as an error. A
+// call to exit returns an ExitSignal. Generators that did not
+// finish are closed
 func (i *Interpreter) Interpret(statements []expressions.Stmt) error {
+	// Generators left unfinished would keep their goroutines
+	defer i.Generators.CloseAll()
 	for _, statement := range statements {
 		_, err := i.Execute(statement)
 		if err != nil {
 			return err
 		}
+		// A task may have called exit
+		if exit := i.Loop.Exited(); exit != nil {
+			return exit
+		}
 	}
-	return nil
+	i.Loop.Run()
+	if exit := i.Loop.Exited(); exit != nil {
+		return exit
+	}
+	return i.Loop.Unhandled()
 }
 
-// stringify converts a value to its string representation
+// stringify converts a value to its string representation, an
+// error from a __str__ method falls back to the default one
 func (i *Interpreter) stringify(value interface{}) string {
+	s, err := i.Stringify(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return s
+}
+
+// Stringify converts a value to its string representation,
+// instances are converted by their __str__ method if they have one
+func (i *Interpreter) Stringify(value interface{}) (string, error) {
+	return i.stringifyValue(value, make(map[interface{}]bool))
+}
+
+// stringifyValue converts a value to a string, visiting holds the
+// lists and maps being converted so a list or map containing
+// itself is written as [...] or {...}
+func (i *Interpreter) stringifyValue(value interface{}, visiting map[interface{}]bool) (string, error) {
 	if value == nil {
-		return "nil"
+		return "nil", nil
+	}
+	switch v := value.(type) {
+	case float64:
+		return formatFloat(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case *big.Int:
+		return v.String(), nil
+	case *List:
+		if visiting[v] {
+			return "[...]", nil
+		}
+		visiting[v] = true
+		defer delete(visiting, v)
+		elements := make([]string, len(v.Elements))
+		for index, element := range v.Elements {
+			s, err := i.stringifyElement(element, visiting)
+			if err != nil {
+				return "", err
+			}
+			elements[index] = s
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	case *Map:
+		if visiting[v] {
+			return "{...}", nil
+		}
+		visiting[v] = true
+		defer delete(visiting, v)
+		entries := make([]string, len(v.Keys))
+		for index, key := range v.Keys {
+			k, err := i.stringifyElement(key, visiting)
+			if err != nil {
+				return "", err
+			}
+			s, err := i.stringifyElement(v.Values[key], visiting)
+			if err != nil {
+				return "", err
+			}
+			entries[index] = k + ": " + s
+		}
+		return "{" + strings.Join(entries, ", ") + "}", nil
+	case *EnumValue:
+		return i.stringifyEnumValue(v, visiting)
+	case *Instance:
+		if _, ok := v.ClassName.Methods[STR_METHOD]; ok {
+			result, err := i.CallMethod(v, STR_METHOD, nil)
+			if err != nil {
+				return "", err
+			}
+			s, ok := result.(string)
+			if !ok {
+				return "", fmt.Errorf("%s of %s must return a string", STR_METHOD, v.ClassName.Name)
+			}
+			return s, nil
+		}
+		return v.ToString(), nil
 	}
-	if num, ok := value.(float64); ok {
-		return fmt.Sprintf("%g", num)
+	return fmt.Sprintf("%v", value), nil
+}
+
+// stringifyElement converts a value inside a list or a map
+// to a string, strings are quoted
+func (i *Interpreter) stringifyElement(value interface{}, visiting map[interface{}]bool) (string, error) {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s), nil
 	}
-	return fmt.Sprintf("%v", value)
+	return i.stringifyValue(value, visiting)
 }
 
 // VisitBinaryExpr handles Binary Operations
@@ -113,45 +290,38 @@ func (i *Interpreter) VisitBinaryExpr(expr *expressions.Binary) (interface{}, er
 		return nil, err
 	}
 
+	// Operators on an instance call its special methods
+	if instance, ok := left.(*Instance); ok {
+		return i.InstanceBinary(expr.Operator, instance, right)
+	}
+
 	operator := expr.Operator.Type
 
+	// Equality is defined for values of any type
+	switch operator {
+	case token.EQUAL_EQUAL, token.BANG_EQUAL:
+		equal, err := i.IsEqual(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return equal == (operator == token.EQUAL_EQUAL), nil
+	}
+
 	if i.IsString(left) && i.IsString(right) && operator == token.PLUS {
 		return left.(string) + right.(string), nil
 	}
 
-	if !i.IsNumber(left) || !i.IsNumber(right) {
-		return nil, i.RuntimeError(expr.Operator, "Binary operations require both operands to be numbers or strings")
+	// Times and durations have arithmetic and comparisons of
+	// their own
+	if result, ok, err := i.TimeBinary(expr.Operator, left, right); ok {
+		return result, err
 	}
 
-	leftNum, rightNum := left.(float64), right.(float64)
-
-	switch operator {
-	case token.MINUS:
-		return leftNum - rightNum, nil
-	case token.STAR:
-		return leftNum * rightNum, nil
-	case token.SLASH:
-		if rightNum == 0 {
-			return nil, i.RuntimeError(expr.Operator, "Division by zero")
-		}
-		return leftNum / rightNum, nil
-	case token.PLUS:
-		return leftNum + rightNum, nil
-	case token.GREATER:
-		return leftNum > rightNum, nil
-	case token.GREATER_EQUAL:
-		return leftNum >= rightNum, nil
-	case token.LESS:
-		return leftNum < rightNum, nil
-	case token.LESS_EQUAL:
-		return leftNum <= rightNum, nil
-	case token.EQUAL_EQUAL:
-		return i.IsEqual(left, right), nil
-	case token.BANG_EQUAL:
-		return !i.IsEqual(left, right), nil
+	if !i.IsNumber(left) || !i.IsNumber(right) {
+		return nil, i.RuntimeError(expr.Operator, "Binary operations require both operands to be numbers or strings")
 	}
 
-	return nil, i.RuntimeError(expr.Operator, "Unknown operator")
+	return i.NumberBinary(expr.Operator, left, right)
 }
 
 // VisitGroupingExpr handles Grouping Operations
@@ -171,7 +341,12 @@ func (i *Interpreter) VisitUnaryExpr(expr *expressions.Unary) (interface{}, erro
 		if !i.IsNumber(right) {
 			return nil, i.RuntimeError(expr.Operator, "Operand must be a number")
 		}
-		return -right.(float64), nil
+		return i.NegateNumber(right), nil
+	case token.TILDE:
+		if !i.IsInteger(right) {
+			return nil, i.RuntimeError(expr.Operator, "Operand of '~' must be an integer")
+		}
+		return i.BitwiseNot(right), nil
 	case token.BANG:
 		return !i.IsTruthy(right), nil
 	}
@@ -203,14 +378,21 @@ func (i *Interpreter) VisitPrintStatementStmt(stmt *expressions.PrintStatement)
 		return nil, err
 	}
 
-	fmt.Println(value)
+	s, err := i.Stringify(value)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(s)
 	return nil, nil
 }
 
 // IsNumber checks if an object is a number
 func (i *Interpreter) IsNumber(object interface{}) bool {
-	_, ok := object.(float64)
-	return ok
+	switch object.(type) {
+	case float64, int64, *big.Int:
+		return true
+	}
+	return false
 }
 
 // IsString checks if an object is a string
@@ -232,15 +414,113 @@ func (i *Interpreter) IsTruthy(expr interface{}) bool {
 	}
 }
 
-// IsEqual checks if two objects are equal
-func (i *Interpreter) IsEqual(a, b interface{}) bool {
+// IsEqual checks if two objects are equal, an error is returned
+// when an __eq__ method it calls fails
+func (i *Interpreter) IsEqual(a, b interface{}) (bool, error) {
+	return i.isEqual(a, b, make(map[comparison]bool))
+}
+
+// comparison is a pair of lists, maps or instances being compared
+type comparison struct {
+	a, b interface{}
+}
+
+// isEqual checks if two objects are equal, comparing holds the
+// pairs being compared further up, which are taken as equal so
+// values containing themselves can be compared
+func (i *Interpreter) isEqual(a, b interface{}, comparing map[comparison]bool) (bool, error) {
 	if a == nil && b == nil {
-		return true
+		return true, nil
 	}
 	if a == nil || b == nil {
-		return false
+		return false, nil
 	}
-	return reflect.DeepEqual(a, b)
+	// Numbers are equal by value, whatever their representation
+	if i.IsNumber(a) && i.IsNumber(b) {
+		if math.IsNaN(toFloat(a)) || math.IsNaN(toFloat(b)) {
+			return false, nil
+		}
+		return compareNumbers(a, b) == 0, nil
+	}
+	// Lists and maps are equal when their elements are
+	switch x := a.(type) {
+	case *EnumValue:
+		return i.isEqualEnumValue(x, b, comparing)
+	case *Time:
+		// The same instant is equal in any time zone
+		y, ok := b.(*Time)
+		return ok && x.Time.Equal(y.Time), nil
+	case *Instance:
+		if _, ok := x.ClassName.Methods[EQ_METHOD]; ok {
+			result, err := i.CallMethod(x, EQ_METHOD, []interface{}{b})
+			if err != nil {
+				return false, err
+			}
+			return i.IsTruthy(result), nil
+		}
+		// Instances of a struct are equal when their fields are,
+		// the fields are copied under the lock of each instance
+		y, ok := b.(*Instance)
+		if !ok || x.ClassName != y.ClassName {
+			return false, nil
+		}
+		if x == y || comparing[comparison{x, y}] {
+			return true, nil
+		}
+		comparing[comparison{x, y}] = true
+		defer delete(comparing, comparison{x, y})
+		xFields, yFields := x.CopyFields(), y.CopyFields()
+		if len(xFields) != len(yFields) {
+			return false, nil
+		}
+		for name, value := range xFields {
+			other, ok := yFields[name]
+			if !ok {
+				return false, nil
+			}
+			if equal, err := i.isEqual(value, other, comparing); !equal || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case *List:
+		y, ok := b.(*List)
+		if !ok || x.Len() != y.Len() {
+			return false, nil
+		}
+		if x == y || comparing[comparison{x, y}] {
+			return true, nil
+		}
+		comparing[comparison{x, y}] = true
+		defer delete(comparing, comparison{x, y})
+		for index := range x.Elements {
+			if equal, err := i.isEqual(x.Elements[index], y.Elements[index], comparing); !equal || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case *Map:
+		y, ok := b.(*Map)
+		if !ok || x.Len() != y.Len() {
+			return false, nil
+		}
+		if x == y || comparing[comparison{x, y}] {
+			return true, nil
+		}
+		comparing[comparison{x, y}] = true
+		defer delete(comparing, comparison{x, y})
+		for _, key := range x.Keys {
+			value, ok := y.Values[key]
+			if !ok {
+				return false, nil
+			}
+			if equal, err := i.isEqual(x.Values[key], value, comparing); !equal || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return reflect.DeepEqual(a, b), nil
 }
 
 // VisitVariableExpr implements the missing method for ExprVisitor
@@ -277,17 +557,27 @@ func (i *Interpreter) VisitAssignExpr(expr *expressions.Assign) (interface{}, er
 		return nil, err
 	}
 
+	if err := i.AssignVariable(expr, expr.Name, value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// AssignVariable assigns to a variable at the scope the
+// resolver found for the expression
+func (i *Interpreter) AssignVariable(expr expressions.Expr, name token.Token, value interface{}) error {
 	distance, ok := i.Locals[expr]
 	if !ok {
-		i.Environment.Assign(expr.Name, value)
-	} else {
-		// Assign at the particular scope
-		i.Environment.AssignAt(distance, &expr.Name, value)
+		if err := i.Globals.Assign(name, value); err != nil {
+			return i.RuntimeError(name, err.Error())
+		}
+		return nil
 	}
 
-	// Assign in the environment
-
-	return value, nil
+	// Assign at the particular scope
+	i.Environment.AssignAt(distance, &name, value)
+	return nil
 }
 
 // VisitBlockStmt handles the interpretation of a block
@@ -346,9 +636,15 @@ func (i *Interpreter) VisitLogicalExpr(expr *expressions.Logical) (interface{},
 		return nil, err
 	}
 
-	// Check for if the logical expression is OR
-	// or AND
-	if expr.Operator.Type == token.OR {
+	// Check for if the logical expression is OR,
+	// AND or a null coalescing ??
+	if expr.Operator.Type == token.QUESTION_QUESTION {
+		// Null coalescing :: the right expression is only
+		// evaluated when left is nil
+		if left != nil {
+			return left, nil
+		}
+	} else if expr.Operator.Type == token.OR {
 		// Or expression :: Short circuit the or expression
 		// i.e if left is true return immediately
 		if i.IsTruthy(left) {
@@ -374,6 +670,20 @@ func (i *Interpreter) VisitLogicalExpr(expr *expressions.Logical) (interface{},
 	return right, nil
 }
 
+// VisitConditionalExpr handles the execution of the ternary
+// conditional operator, only the chosen branch is evaluated
+func (i *Interpreter) VisitConditionalExpr(expr *expressions.Conditional) (interface{}, error) {
+	condition, err := i.Evaluate(expr.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.IsTruthy(condition) {
+		return i.Evaluate(expr.ThenBranch)
+	}
+	return i.Evaluate(expr.ElseBranch)
+}
+
 // VisitWhileStatementStmt handles execution of while statements
 func (i *Interpreter) VisitWhileStatementStmt(stmt *expressions.WhileStatement) (interface{}, error) {
 	for {
@@ -0,0 +1,88 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConditionalExpressions(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{`true ? 1 : 2`, int64(1)},
+		{`nil ? 1 : 2`, int64(2)},
+		// The conditional is right associative and binds looser
+		// than or and ??
+		{`true ? 1 : 2 ? 3 : 4`, int64(1)},
+		{`false ? 1 : false ? 3 : 4`, int64(4)},
+		{`1 or 2 ? "a" : "b"`, "a"},
+		{`false ? 1 : nil ?? 5`, int64(5)},
+		{`nil ?? 2`, int64(2)},
+		// Only nil is replaced, not every falsy value
+		{`false ?? 3`, false},
+		{`nil ?? false ?? 3`, false},
+		{`nil ?? nil ?? 3`, int64(3)},
+		{`nil?.upper`, nil},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %#v, want %#v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestConditionalShortCircuits(t *testing.T) {
+	execution, err := run(t, `
+var calls = 0;
+def bump() { calls = calls + 1; return calls; }
+var a = 1 ?? bump();
+var b = true ? 1 : bump();
+var c = false ? bump() : 2;
+var d = nil?.field(bump());
+var e = nil ?? bump();
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "calls"); got != int64(1) {
+		t.Errorf("got %v calls of bump, want 1", got)
+	}
+}
+
+func TestOptionalFields(t *testing.T) {
+	execution, err := run(t, `
+struct Node {
+  construct(next) { this.next = next; this.name = "node"; }
+}
+var list = Node(Node(nil));
+var second = list?.next?.name;
+var third = list?.next?.next?.name;
+var missing = list.next.next?.next?.name ?? "none";
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"second", "node"},
+		{"third", nil},
+		{"missing", "none"},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestOptionalChainingOnlyGuardsItsOwnObject(t *testing.T) {
+	// Each ?. checks the object on its left, a plain . after it
+	// still reads a property of nil
+	_, err := run(t, `def f(o) { return o?.a.b; } f(nil);`)
+	if err == nil || !strings.Contains(err.Error(), "Only objects have properties") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// This file handles the json module converting between JSON
+// text and the values of the language. Parsed objects keep the
+// order of their keys and numbers without a fraction or an
+// exponent are integers. Instances are written as objects of
+// their fields and variants without values as their name,
+// functions, values containing themselves and floats that are
+// not finite can not be written
+
+// DefineJSONModule defines the json module
+func (i *Interpreter) DefineJSONModule() {
+	module := NewModule("json")
+	module.Function("parse", 1, 1, jsonParse)
+	module.Function("stringify", 1, 2, jsonStringify)
+	i.Globals.Define(module.Name, module)
+}
+
+// jsonParse converts JSON text to maps, lists, numbers, strings,
+// booleans and nil. Objects keep the order of their keys and
+// numbers without a fraction or an exponent are integers
+func jsonParse(i *Interpreter, args []interface{}) (interface{}, error) {
+	text, err := stringArg("json.parse", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	value, err := decodeJSON(decoder)
+	if err == nil {
+		// Nothing can follow the value
+		if _, next := decoder.Token(); next != io.EOF {
+			err = errors.New("unexpected data after the value")
+			if next != nil {
+				err = next
+			}
+		}
+	}
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errors.New("json.parse: " + err.Error() + " at offset " + strconv.FormatInt(decoder.InputOffset(), 10))
+	}
+	return value, nil
+}
+
+// decodeJSON decodes the next value of a decoder
+func decodeJSON(decoder *json.Decoder) (interface{}, error) {
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		if v == '[' {
+			elements := make([]interface{}, 0)
+			for decoder.More() {
+				element, err := decodeJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			// The closing bracket
+			if _, err := decoder.Token(); err != nil {
+				return nil, err
+			}
+			return NewList(elements), nil
+		}
+
+		object := NewMap()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(key, value)
+		}
+		// The closing brace
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case json.Number:
+		return jsonNumber(v)
+	}
+	// Strings, booleans and nil
+	return t, nil
+}
+
+// jsonNumber converts a JSON number to an integer when it has no
+// fraction or exponent and to a float otherwise
+func jsonNumber(number json.Number) (interface{}, error) {
+	text := number.String()
+	if !strings.ContainsAny(text, ".eE") {
+		if integer, ok := new(big.Int).SetString(text, 10); ok {
+			return normalizeBig(integer), nil
+		}
+	}
+	return number.Float64()
+}
+
+// jsonStringify converts a value to JSON text, indented by a
+// number of spaces or a string given as the second argument.
+// Instances are written as objects of their fields
+func jsonStringify(i *Interpreter, args []interface{}) (interface{}, error) {
+	indent := ""
+	if len(args) == 2 {
+		switch v := args[1].(type) {
+		case nil:
+		case int64:
+			if v < 0 || v > 10 {
+				return nil, errors.New("json.stringify expects an indent from 0 to 10 spaces but got " + i.stringify(v))
+			}
+			indent = strings.Repeat(" ", int(v))
+		case string:
+			indent = v
+		default:
+			return nil, errors.New("json.stringify expects an indent that is a number of spaces or a string but got " + i.stringify(v))
+		}
+	}
+
+	encoder := &jsonEncoder{interpreter: i, visiting: make(map[interface{}]bool)}
+	if err := encoder.encode(args[0]); err != nil {
+		return nil, errors.New("json.stringify: " + err.Error())
+	}
+	if indent == "" {
+		return encoder.buffer.String(), nil
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, encoder.buffer.Bytes(), "", indent); err != nil {
+		return nil, errors.New("json.stringify: " + err.Error())
+	}
+	return indented.String(), nil
+}
+
+// jsonEncoder writes values as compact JSON, visiting holds the
+// lists, maps and instances being written to detect cycles
+type jsonEncoder struct {
+	interpreter *Interpreter
+	buffer      bytes.Buffer
+	visiting    map[interface{}]bool
+}
+
+func (e *jsonEncoder) encode(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		e.buffer.WriteString("null")
+	case bool:
+		e.buffer.WriteString(strconv.FormatBool(v))
+	case int64:
+		e.buffer.WriteString(strconv.FormatInt(v, 10))
+	case *big.Int:
+		e.buffer.WriteString(v.String())
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return errors.New("can not encode " + formatFloat(v))
+		}
+		// A whole float stays a float when it is parsed again
+		text := formatFloat(v)
+		if !strings.ContainsAny(text, ".eE") {
+			text += ".0"
+		}
+		e.buffer.WriteString(text)
+	case string:
+		e.encodeString(v)
+	case *List:
+		return e.visit(v, func() error {
+			e.buffer.WriteByte('[')
+			for index, element := range v.Elements {
+				if index > 0 {
+					e.buffer.WriteByte(',')
+				}
+				if err := e.encode(element); err != nil {
+					return err
+				}
+			}
+			e.buffer.WriteByte(']')
+			return nil
+		})
+	case *Map:
+		return e.visit(v, func() error {
+			// Keys that are not strings are written as strings
+			keys := make([]string, len(v.Keys))
+			for index, key := range v.Keys {
+				keys[index] = e.interpreter.stringify(key)
+			}
+			return e.encodeObject(keys, func(index int) interface{} {
+				return v.Values[v.Keys[index]]
+			})
+		})
+	case *Instance:
+		return e.visit(v, func() error {
+			fields := v.CopyFields()
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return e.encodeObject(names, func(index int) interface{} {
+				return fields[names[index]]
+			})
+		})
+	case *EnumValue:
+		// Only a variant without values is a single value
+		if v.Variant.Fields != nil {
+			return errors.New("can not encode " + e.interpreter.stringify(v) + ", only variants without values can be encoded")
+		}
+		e.encodeString(v.Variant.Name)
+	case Callable:
+		return errors.New("can not encode the function " + callableName(v))
+	default:
+		return errors.New("can not encode " + e.interpreter.stringify(v))
+	}
+	return nil
+}
+
+// visit writes a list, a map or an instance, failing when the
+// value is already being written further up
+func (e *jsonEncoder) visit(value interface{}, write func() error) error {
+	if e.visiting[value] {
+		return errors.New("can not encode a value that contains itself")
+	}
+	e.visiting[value] = true
+	defer delete(e.visiting, value)
+	return write()
+}
+
+// encodeObject writes an object of keys and the values at the
+// same index
+func (e *jsonEncoder) encodeObject(keys []string, value func(index int) interface{}) error {
+	e.buffer.WriteByte('{')
+	for index, key := range keys {
+		if index > 0 {
+			e.buffer.WriteByte(',')
+		}
+		e.encodeString(key)
+		e.buffer.WriteByte(':')
+		if err := e.encode(value(index)); err != nil {
+			return err
+		}
+	}
+	e.buffer.WriteByte('}')
+	return nil
+}
+
+func (e *jsonEncoder) encodeString(s string) {
+	// JSON does not need <, > and & escaped for HTML
+	encoder := json.NewEncoder(&e.buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	// Encode ends the value with a newline
+	e.buffer.Truncate(e.buffer.Len() - 1)
+}
@@ -0,0 +1,87 @@
+package interpreter_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	execution, err := run(t, `
+struct P { construct(x) { this.x = x; this.name = "p"; } }
+enum C { Red, Big(n) }
+var text = json.stringify({"b": [1, 2.5, true, nil, "é"], "a": P(1), "c": C.Red, 3: {}});
+var back = json.parse(text);
+var same = json.stringify(back) == text;
+var keys = "{}".format(back);
+var indented = json.stringify([1, {"a": []}], 2);
+var custom = json.stringify([1], "--");
+var unchanged = json.parse(json.stringify(back)) == back;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		// Fields of an instance are written in sorted order
+		{"text", `{"b":[1,2.5,true,null,"é"],"a":{"name":"p","x":1},"c":"Red","3":{}}`},
+		{"same", true},
+		{"keys", `{"b": [1, 2.5, true, nil, "é"], "a": {"name": "p", "x": 1}, "c": "Red", "3": {}}`},
+		{"indented", "[\n  1,\n  {\n    \"a\": []\n  }\n]"},
+		{"custom", "[\n--1\n]"},
+		{"unchanged", true},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestJSONNumbers(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{`json.parse("1")`, int64(1)},
+		{`json.parse("-7")`, int64(-7)},
+		{`json.parse("1.0")`, 1.0},
+		{`json.parse("1e2")`, 100.0},
+		{`json.stringify(2 ** 70)`, "1180591620717411303424"},
+		{`json.stringify(0.1)`, "0.1"},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %#v, want %#v", test.expression, got, test.want)
+		}
+	}
+
+	huge, ok := evaluate(t, `json.parse("12345678901234567890123")`).(*big.Int)
+	if !ok || huge.String() != "12345678901234567890123" {
+		t.Errorf("got %v", huge)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`json.parse("[1,");`, "json.parse: "},
+		{`json.parse("[1] 2");`, "unexpected data after the value"},
+		{`def f() {} json.stringify([f]);`, "can not encode the function f"},
+		{`enum C { Big(n) } json.stringify(C.Big(1));`, "only variants without values can be encoded"},
+		{`var l = [1]; l[0] = l; json.stringify(l);`, "can not encode a value that contains itself"},
+		{`json.stringify(math.NaN);`, "can not encode NaN"},
+		{`json.stringify(1, 11);`, "indent from 0 to 10 spaces"},
+		{`json.stringify(1, [true][0]);`, "an indent that is a number of spaces or a string"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package interpreter
+
+import (
+	"sync/atomic"
+
+	"github.com/Atul-Ranjan12/parser/expressions"
+)
+
+// List represents a list of values in runtime
+type List struct {
+	Elements []interface{}
+	// A frozen list can not be changed, the flag is atomic so
+	// tasks sharing the list can read it
+	frozen atomic.Bool
+}
+
+// Freeze makes the list immutable
+func (l *List) Freeze() {
+	l.frozen.Store(true)
+}
+
+// IsFrozen checks if the list can not be changed
+func (l *List) IsFrozen() bool {
+	return l.frozen.Load()
+}
+
+// NewList creates a new list from its elements
+func NewList(elements []interface{}) *List {
+	return &List{
+		Elements: elements,
+	}
+}
+
+// Len returns the number of elements in the list
+func (l *List) Len() int {
+	return len(l.Elements)
+}
+
+// VisitListLiteralExpr evaluates the elements of a list literal,
+// spread elements are expanded in place
+func (i *Interpreter) VisitListLiteralExpr(expr *expressions.ListLiteral) (interface{}, error) {
+	elements, err := i.EvaluateArguments(expr.Elements)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewList(elements), nil
+}
+
+// VisitSpreadExpr is only reached when ... is used outside of
+// a call or a list literal
+func (i *Interpreter) VisitSpreadExpr(expr *expressions.Spread) (interface{}, error) {
+	return nil, i.RuntimeError(expr.Ellipsis, "Can only spread in calls and list literals")
+}
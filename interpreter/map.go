@@ -0,0 +1,109 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	"github.com/Atul-Ranjan12/parser/expressions"
+)
+
+// Map represents a map of keys to values in runtime, the keys
+// are kept in insertion order
+type Map struct {
+	Keys   []interface{}
+	Values map[interface{}]interface{}
+	// A frozen map can not be changed, the flag is atomic so
+	// tasks sharing the map can read it
+	frozen atomic.Bool
+}
+
+// Freeze makes the map immutable
+func (m *Map) Freeze() {
+	m.frozen.Store(true)
+}
+
+// IsFrozen checks if the map can not be changed
+func (m *Map) IsFrozen() bool {
+	return m.frozen.Load()
+}
+
+// NewMap creates a new empty map
+func NewMap() *Map {
+	return &Map{
+		Keys:   make([]interface{}, 0),
+		Values: make(map[interface{}]interface{}),
+	}
+}
+
+// MapKey converts a value to the key it is stored under. Only
+// strings, booleans and numbers can be keys, a whole float is
+// the same key as the equal integer
+func MapKey(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string, bool, int64:
+		return v, nil
+	case *EnumValue:
+		// A variant without values has a single value
+		if v.Variant.Fields == nil {
+			return v, nil
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), nil
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("Value can not be used as a map key")
+}
+
+// Get returns the value of a key
+func (m *Map) Get(key interface{}) (interface{}, bool, error) {
+	k, err := MapKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok := m.Values[k]
+	return value, ok, nil
+}
+
+// Set sets the value of a key
+func (m *Map) Set(key interface{}, value interface{}) error {
+	if m.IsFrozen() {
+		return fmt.Errorf("Can not change a frozen map")
+	}
+	k, err := MapKey(key)
+	if err != nil {
+		return err
+	}
+	if _, ok := m.Values[k]; !ok {
+		m.Keys = append(m.Keys, k)
+	}
+	m.Values[k] = value
+	return nil
+}
+
+// Len returns the number of entries in the map
+func (m *Map) Len() int {
+	return len(m.Keys)
+}
+
+// VisitMapLiteralExpr evaluates the entries of a map literal
+func (i *Interpreter) VisitMapLiteralExpr(expr *expressions.MapLiteral) (interface{}, error) {
+	m := NewMap()
+	for index, keyExpr := range expr.Keys {
+		key, err := i.Evaluate(keyExpr)
+		if err != nil {
+			return nil, err
+		}
+		value, err := i.Evaluate(expr.Values[index])
+		if err != nil {
+			return nil, err
+		}
+		if err := m.Set(key, value); err != nil {
+			return nil, i.RuntimeError(expr.Brace, err.Error())
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,152 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Atul-Ranjan12/environment"
+	"github.com/Atul-Ranjan12/parser/expressions"
+)
+
+// This file handles interpretation of match statements
+
+// VisitMatchStmt executes the first arm whose pattern matches
+// the value, nothing is executed when no arm matches
+func (i *Interpreter) VisitMatchStmt(stmt *expressions.Match) (interface{}, error) {
+	value, err := i.Evaluate(stmt.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arm := range stmt.Arms {
+		for _, pattern := range arm.Patterns {
+			// Every arm binds its pattern variables in a new scope
+			env := environment.NewEnvironment(i.Environment)
+			matched, err := i.MatchPattern(pattern, value, env)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+
+			if arm.Guard != nil {
+				guard, err := i.EvaluateIn(arm.Guard, env)
+				if err != nil {
+					return nil, err
+				}
+				if !i.IsTruthy(guard) {
+					continue
+				}
+			}
+
+			return nil, i.ExecuteBlock([]expressions.Stmt{arm.Body}, env)
+		}
+	}
+
+	return nil, nil
+}
+
+// EvaluateIn evaluates an expression in the given environment
+func (i *Interpreter) EvaluateIn(expr expressions.Expr, env *environment.Environment) (interface{}, error) {
+	previous := i.Environment
+	i.Environment = env
+	defer func() { i.Environment = previous }()
+
+	return i.Evaluate(expr)
+}
+
+// MatchPattern checks if a value matches a pattern, defining
+// the variables bound by the pattern in env
+func (i *Interpreter) MatchPattern(pattern expressions.Pattern, value interface{}, env *environment.Environment) (bool, error) {
+	switch p := pattern.(type) {
+	case *expressions.Wildcard:
+		return true, nil
+
+	case *expressions.Binding:
+		env.Define(p.Name.Lexeme, value)
+		return true, nil
+
+	case *expressions.Constant:
+		return i.IsEqual(p.Value, value)
+
+	case *expressions.Sequence:
+		list, ok := value.(*List)
+		if !ok || list.Len() != len(p.Elements) {
+			return false, nil
+		}
+		for index, element := range p.Elements {
+			matched, err := i.MatchPattern(element, list.Elements[index], env)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case *expressions.Record:
+		if p.Class != nil {
+			instance, ok := value.(*Instance)
+			if !ok {
+				return false, nil
+			}
+
+			// The struct is resolved inside the scope of the arm
+			class, err := i.EvaluateIn(p.Class, env)
+			if err != nil {
+				return false, err
+			}
+			if _, ok := class.(*Class); !ok {
+				return false, i.RuntimeError(p.Class.Name, "Struct pattern requires a struct")
+			}
+			if instance.ClassName != class {
+				return false, nil
+			}
+		}
+
+		for index, field := range p.Fields {
+			fieldValue, err := i.FieldOf(value, field)
+			if err != nil {
+				// A missing field is not a match
+				return false, nil
+			}
+			matched, err := i.MatchPattern(p.Patterns[index], fieldValue, env)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case *expressions.Variant:
+		enumValue, err := i.EvaluateIn(p.Enum, env)
+		if err != nil {
+			return false, err
+		}
+		enum, ok := enumValue.(*Enum)
+		if !ok {
+			return false, i.RuntimeError(p.Enum.Name, "Variant pattern requires an enum")
+		}
+		variant, ok := enum.Variants[p.Name.Lexeme]
+		if !ok {
+			return false, i.RuntimeError(p.Name, "Undefined variant "+p.Name.Lexeme+" of enum "+enum.Name)
+		}
+		if p.HasPayload && variant.Fields == nil {
+			return false, i.RuntimeError(p.Name, "Variant "+variant.Name+" carries no values")
+		}
+		if p.HasPayload && len(p.Patterns) != len(variant.Fields) {
+			return false, i.RuntimeError(p.Name, fmt.Sprintf("Variant %s carries %d values but the pattern has %d", variant.Name, len(variant.Fields), len(p.Patterns)))
+		}
+
+		v, ok := value.(*EnumValue)
+		if !ok || v.Variant != variant {
+			return false, nil
+		}
+		for index, pattern := range p.Patterns {
+			matched, err := i.MatchPattern(pattern, v.Payload[index], env)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
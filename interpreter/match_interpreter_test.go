@@ -0,0 +1,119 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	describe := `
+struct Point { construct(x, y) { this.x = x; this.y = y; } }
+def describe(v) {
+  var result = "none";
+  match (v) {
+    1, 2 => result = "small";
+    -1 => result = "minus one";
+    "x" => result = "x";
+    true => result = "true";
+    nil => result = "nil";
+    [] => result = "empty";
+    [a, [b, _]] => result = "nested {} {}".format(a, b);
+    [a, b] => result = "pair {} {}".format(a, b);
+    Point{x: 0, y} => result = "on the y axis at {}".format(y);
+    Point{x, y} if x == y => result = "diagonal {}".format(x);
+    Point{x, y} => result = "point {} {}".format(x, y);
+    {name} => result = "named " + name;
+    n if n == 500 => result = "five hundred";
+    _ => result = "other";
+  }
+  return result;
+}
+`
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"1", "small"},
+		{"2", "small"},
+		{"1.0", "small"},
+		{"-1", "minus one"},
+		{`"x"`, "x"},
+		{"true", "true"},
+		{"nil", "nil"},
+		{"[]", "empty"},
+		{"[1, [2, 3]]", "nested 1 2"},
+		{"[1, 2]", "pair 1 2"},
+		{"[1, 2, 3]", "other"},
+		{"Point(0, 5)", "on the y axis at 5"},
+		{"Point(3, 3)", "diagonal 3"},
+		{"Point(3, 4)", "point 3 4"},
+		{`{"name": "m"}`, "named m"},
+		{`{"other": "m"}`, "other"},
+		{"500", "five hundred"},
+		{"5", "other"},
+	}
+	for _, test := range tests {
+		execution, err := run(t, describe+"var result = describe("+test.value+");")
+		if err != nil {
+			t.Errorf("%s: %v", test.value, err)
+			continue
+		}
+		if got := global(t, execution, "result"); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestMatchWithoutMatchingArm(t *testing.T) {
+	execution, err := run(t, `
+var result = "unchanged";
+match (3) {
+  1 => result = "one";
+  n if n > 5 => result = "big";
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "result"); got != "unchanged" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestMatchBindingsAreScopedToTheArm(t *testing.T) {
+	execution, err := run(t, `
+var x = "outer";
+var inner = nil;
+match ([1, 2]) {
+  [x, y] => inner = x;
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "x"); got != "outer" {
+		t.Errorf("the pattern changed the outer x to %v", got)
+	}
+	if got := global(t, execution, "inner"); got != int64(1) {
+		t.Errorf("got inner %v", got)
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`struct P { construct() {} } var NotAStruct = 1; match (P()) { NotAStruct{x} => println x; }`, "Struct pattern requires a struct"},
+		// The checker reports these when it knows the enum
+		{`enum E { A, B(x) } def f(e) { match (E.A) { e.C => println 1; } } f(E);`, "Undefined variant C of enum E"},
+		{`enum E { A, B(x) } def f(e) { match (E.A) { e.A(x) => println x; } } f(E);`, "Variant A carries no values"},
+		{`enum E { A, B(x) } def f(e) { match (E.A) { e.B(x, y) => println x; } } f(E);`, "Variant B carries 1 values but the pattern has 2"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package interpreter
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// This file handles the math module, the numeric functions
+// and constants and a seedable random number generator.
+// floor, ceil and round give integers and round halves away
+// from zero, min and max take numbers or a list and randint
+// includes both bounds
+
+// DefineMathModule defines the math module
+func (i *Interpreter) DefineMathModule() {
+	module := NewModule("math")
+	module.Members["PI"] = math.Pi
+	module.Members["E"] = math.E
+	module.Members["INF"] = math.Inf(1)
+	module.Members["NaN"] = math.NaN()
+
+	module.Function("sqrt", 1, 1, floatFunction("sqrt", math.Sqrt))
+	module.Function("exp", 1, 1, floatFunction("exp", math.Exp))
+	module.Function("sin", 1, 1, floatFunction("sin", math.Sin))
+	module.Function("cos", 1, 1, floatFunction("cos", math.Cos))
+	module.Function("tan", 1, 1, floatFunction("tan", math.Tan))
+	module.Function("asin", 1, 1, floatFunction("asin", math.Asin))
+	module.Function("acos", 1, 1, floatFunction("acos", math.Acos))
+	module.Function("atan", 1, 1, floatFunction("atan", math.Atan))
+	module.Function("atan2", 2, 2, mathAtan2)
+	module.Function("pow", 2, 2, mathPow)
+	module.Function("log", 1, 2, mathLog)
+	module.Function("abs", 1, 1, mathAbs)
+	module.Function("floor", 1, 1, roundingFunction("floor", math.Floor))
+	module.Function("ceil", 1, 1, roundingFunction("ceil", math.Ceil))
+	module.Function("round", 1, 2, mathRound)
+	module.Function("min", 1, VARIADIC, extremeFunction("min", -1))
+	module.Function("max", 1, VARIADIC, extremeFunction("max", 1))
+
+	// Each interpreter has its own generator, seeded with the
+	// time unless the script seeds it
+	r := &random{source: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	module.Function("seed", 1, 1, r.seed)
+	module.Function("random", 0, 0, r.random)
+	module.Function("randint", 2, 2, r.randint)
+	module.Function("choice", 1, 1, r.choice)
+	module.Function("shuffle", 1, 1, r.shuffle)
+
+	i.Globals.Define(module.Name, module)
+}
+
+// numberArg checks that an argument of a math function is a number
+func numberArg(i *Interpreter, name string, arg interface{}) (interface{}, error) {
+	if !i.IsNumber(arg) {
+		return nil, errors.New("math." + name + " expects a number but got " + i.stringify(arg))
+	}
+	return arg, nil
+}
+
+// integerArg checks that an argument of a math function is an integer
+func integerArg(i *Interpreter, name string, arg interface{}) (*big.Int, error) {
+	if !i.IsInteger(arg) {
+		return nil, errors.New("math." + name + " expects an integer but got " + i.stringify(arg))
+	}
+	return toBig(arg), nil
+}
+
+// listArg checks that an argument of a math function is a list
+func listArg(i *Interpreter, name string, arg interface{}) (*List, error) {
+	list, ok := arg.(*List)
+	if !ok {
+		return nil, errors.New("math." + name + " expects a list but got " + i.stringify(arg))
+	}
+	return list, nil
+}
+
+// floatFunction creates a math function of one number giving a float
+func floatFunction(name string, function func(float64) float64) func(i *Interpreter, args []interface{}) (interface{}, error) {
+	return func(i *Interpreter, args []interface{}) (interface{}, error) {
+		x, err := numberArg(i, name, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return function(toFloat(x)), nil
+	}
+}
+
+func mathAtan2(i *Interpreter, args []interface{}) (interface{}, error) {
+	y, err := numberArg(i, "atan2", args[0])
+	if err != nil {
+		return nil, err
+	}
+	x, err := numberArg(i, "atan2", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return math.Atan2(toFloat(y), toFloat(x)), nil
+}
+
+// mathPow always gives a float, unlike '**' on integers
+func mathPow(i *Interpreter, args []interface{}) (interface{}, error) {
+	x, err := numberArg(i, "pow", args[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := numberArg(i, "pow", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return math.Pow(toFloat(x), toFloat(y)), nil
+}
+
+// mathLog gives the natural logarithm, or the logarithm in the
+// base given as the second argument
+func mathLog(i *Interpreter, args []interface{}) (interface{}, error) {
+	x, err := numberArg(i, "log", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		return math.Log(toFloat(x)), nil
+	}
+	base, err := numberArg(i, "log", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return math.Log(toFloat(x)) / math.Log(toFloat(base)), nil
+}
+
+// mathAbs keeps integers integers
+func mathAbs(i *Interpreter, args []interface{}) (interface{}, error) {
+	x, err := numberArg(i, "abs", args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch v := x.(type) {
+	case int64:
+		if v >= 0 {
+			return v, nil
+		}
+		if v == math.MinInt64 {
+			return new(big.Int).Neg(big.NewInt(v)), nil
+		}
+		return -v, nil
+	case *big.Int:
+		return normalizeBig(new(big.Int).Abs(v)), nil
+	}
+	return math.Abs(toFloat(x)), nil
+}
+
+// floatToInteger converts a whole float to an integer
+func floatToInteger(name string, value float64) (interface{}, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, errors.New("math." + name + " can not convert " + formatFloat(value) + " to an integer")
+	}
+	integer, _ := big.NewFloat(value).Int(nil)
+	return normalizeBig(integer), nil
+}
+
+// roundingFunction creates a math function rounding a number
+// to an integer, integers are returned as they are
+func roundingFunction(name string, function func(float64) float64) func(i *Interpreter, args []interface{}) (interface{}, error) {
+	return func(i *Interpreter, args []interface{}) (interface{}, error) {
+		x, err := numberArg(i, name, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if i.IsInteger(x) {
+			return x, nil
+		}
+		return floatToInteger(name, function(toFloat(x)))
+	}
+}
+
+// mathRound rounds halves away from zero to an integer, or to a
+// float with the number of decimal digits given as the second
+// argument
+func mathRound(i *Interpreter, args []interface{}) (interface{}, error) {
+	x, err := numberArg(i, "round", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		if i.IsInteger(x) {
+			return x, nil
+		}
+		return floatToInteger("round", math.Round(toFloat(x)))
+	}
+
+	digits, ok := args[1].(int64)
+	if !ok {
+		return nil, errors.New("math.round expects a number of digits that is an integer but got " + i.stringify(args[1]))
+	}
+	value := toFloat(x)
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return value, nil
+	}
+	// 10**digits overflows to Inf or underflows to 0 for large
+	// digit counts, where x keeps all its digits or loses them
+	scale := math.Pow(10, float64(digits))
+	if scale == 0 {
+		return 0.0, nil
+	}
+	scaled := value * scale
+	if math.IsInf(scaled, 0) || math.IsNaN(scaled) {
+		return value, nil
+	}
+	return math.Round(scaled) / scale, nil
+}
+
+// extremeFunction creates min or max, taking numbers or a single
+// list of numbers. sign is -1 for the least and 1 for the
+// greatest number, which is returned as it is
+func extremeFunction(name string, sign int) func(i *Interpreter, args []interface{}) (interface{}, error) {
+	return func(i *Interpreter, args []interface{}) (interface{}, error) {
+		values := args
+		if list, ok := args[0].(*List); ok && len(args) == 1 {
+			values = list.Elements
+		}
+		if len(values) == 0 {
+			return nil, errors.New("math." + name + " expects at least one number")
+		}
+
+		var result interface{}
+		for _, value := range values {
+			if _, err := numberArg(i, name, value); err != nil {
+				return nil, err
+			}
+			if result == nil || compareNumbers(value, result) == sign {
+				result = value
+			}
+		}
+		return result, nil
+	}
+}
+
+// random is the random number generator of a math module, it is
+// guarded by a mutex since tasks share the module
+type random struct {
+	mutex  sync.Mutex
+	source *rand.Rand
+}
+
+// seed makes the numbers that follow the same on every run
+func (r *random) seed(i *Interpreter, args []interface{}) (interface{}, error) {
+	seed, ok := args[0].(int64)
+	if !ok {
+		return nil, errors.New("math.seed expects an integer but got " + i.stringify(args[0]))
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.source.Seed(seed)
+	return nil, nil
+}
+
+// random gives a float in [0, 1)
+func (r *random) random(i *Interpreter, args []interface{}) (interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.source.Float64(), nil
+}
+
+// randint gives an integer from a to b, both included
+func (r *random) randint(i *Interpreter, args []interface{}) (interface{}, error) {
+	low, err := integerArg(i, "randint", args[0])
+	if err != nil {
+		return nil, err
+	}
+	high, err := integerArg(i, "randint", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if low.Cmp(high) > 0 {
+		return nil, errors.New("math.randint expects the first bound to be at most the second")
+	}
+
+	count := new(big.Int).Sub(high, low)
+	count.Add(count, big.NewInt(1))
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	value := new(big.Int).Rand(r.source, count)
+	return normalizeBig(value.Add(value, low)), nil
+}
+
+// choice gives a random element of a list
+func (r *random) choice(i *Interpreter, args []interface{}) (interface{}, error) {
+	list, err := listArg(i, "choice", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if list.Len() == 0 {
+		return nil, errors.New("math.choice expects a list that is not empty")
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return list.Elements[r.source.Intn(list.Len())], nil
+}
+
+// shuffle puts the elements of a list in a random order
+func (r *random) shuffle(i *Interpreter, args []interface{}) (interface{}, error) {
+	list, err := listArg(i, "shuffle", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if list.IsFrozen() {
+		return nil, errors.New("math.shuffle can not change a frozen list")
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.source.Shuffle(list.Len(), func(a, b int) {
+		list.Elements[a], list.Elements[b] = list.Elements[b], list.Elements[a]
+	})
+	return nil, nil
+}
@@ -0,0 +1,25 @@
+package interpreter_test
+
+import "testing"
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{"math.round(2.5)", int64(3)},
+		{"math.round(7)", int64(7)},
+		{"math.round(2.345, 2)", 2.35},
+		{"math.round(1234.5, -2)", 1200.0},
+		{"math.round(2.5, 400)", 2.5},
+		{"math.round(1e300, 300)", 1e300},
+		{"math.round(0.0, 400)", 0.0},
+		{"math.round(2.5, -400)", 0.0},
+		{"math.round(1e300, -400)", 0.0},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.expression, got, test.want)
+		}
+	}
+}
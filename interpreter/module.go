@@ -0,0 +1,37 @@
+package interpreter
+
+import "github.com/Atul-Ranjan12/token"
+
+// Module is a named group of the natives and constants of the
+// standard library, its members are read as properties
+// (math.sqrt, math.PI)
+type Module struct {
+	Name    string
+	Members map[string]interface{}
+}
+
+// NewModule creates a module without members
+func NewModule(name string) *Module {
+	return &Module{
+		Name:    name,
+		Members: make(map[string]interface{}),
+	}
+}
+
+func (m *Module) String() string {
+	return "<module " + m.Name + ">"
+}
+
+// Function adds a native to the module, the native is named
+// after the module so its errors say where they come from
+func (m *Module) Function(name string, min, max int, function func(i *Interpreter, args []interface{}) (interface{}, error)) {
+	m.Members[name] = NewVariadicFunction(m.Name+"."+name, min, max, function)
+}
+
+// Get returns a member of the module
+func (m *Module) Get(i *Interpreter, name *token.Token) (interface{}, error) {
+	if member, ok := m.Members[name.Lexeme]; ok {
+		return member, nil
+	}
+	return nil, i.RuntimeError(*name, "Module "+m.Name+" has no member "+name.Lexeme)
+}
@@ -1,6 +1,9 @@
 package interpreter
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // This function defines all native functions
 // of the interpreter -> implements -> Callable
@@ -12,7 +15,12 @@ type Clock struct {
 var _ Callable = (*Clock)(nil)
 
 // Returns the number of arguments of the fucntion
-func (c *Clock) Arity() int {
+func (c *Clock) MinArity() int {
+	return 0
+}
+
+// Returns the number of arguments of the fucntion
+func (c *Clock) MaxArity() int {
 	return 0
 }
 
@@ -25,3 +33,89 @@ func (c *Clock) Call(i *Interpreter, args []interface{}) (interface{}, error) {
 func (c *Clock) String() string {
 	return "<native fn: clock>"
 }
+
+// NativeFunction is a callable implemented in go, it is used
+// for natives that do not need any state of their own
+type NativeFunction struct {
+	Name     string
+	MinArgs  int
+	MaxArgs  int
+	Function func(i *Interpreter, args []interface{}) (interface{}, error)
+}
+
+var _ Callable = (*NativeFunction)(nil)
+
+// NewNativeFunction creates a new native function that
+// takes a fixed number of arguments
+func NewNativeFunction(name string, arity int, function func(i *Interpreter, args []interface{}) (interface{}, error)) *NativeFunction {
+	return NewVariadicFunction(name, arity, arity, function)
+}
+
+// NewVariadicFunction creates a new native function that takes
+// between min and max arguments, max may be VARIADIC
+func NewVariadicFunction(name string, min, max int, function func(i *Interpreter, args []interface{}) (interface{}, error)) *NativeFunction {
+	return &NativeFunction{
+		Name:     name,
+		MinArgs:  min,
+		MaxArgs:  max,
+		Function: function,
+	}
+}
+
+// Returns the least number of arguments of the function
+func (n *NativeFunction) MinArity() int {
+	return n.MinArgs
+}
+
+// Returns the most number of arguments of the function
+func (n *NativeFunction) MaxArity() int {
+	return n.MaxArgs
+}
+
+// Call runs the go implementation of the native
+func (n *NativeFunction) Call(i *Interpreter, args []interface{}) (interface{}, error) {
+	return n.Function(i, args)
+}
+
+// Implements the string function of the native
+func (n *NativeFunction) String() string {
+	return "<native fn: " + n.Name + ">"
+}
+
+// DefineObjectFunctions defines the natives that work on
+// instances, lists and maps
+func (i *Interpreter) DefineObjectFunctions() {
+	i.Define(i.Globals, NewNativeFunction("freeze", 1, freeze), "freeze")
+	i.Define(i.Globals, NewNativeFunction("isFrozen", 1, isFrozen), "isFrozen")
+	i.Define(i.Globals, NewNativeFunction("implements", 2, implements), "implements")
+}
+
+// freeze makes an instance, list or map immutable and returns
+// it, the values it holds are not frozen
+func freeze(i *Interpreter, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case *Instance:
+		v.Freeze()
+	case *List:
+		v.Freeze()
+	case *Map:
+		v.Freeze()
+	default:
+		return nil, fmt.Errorf("freeze expects an instance, a list or a map but got %s", i.stringify(args[0]))
+	}
+	return args[0], nil
+}
+
+// isFrozen checks if a value can not be changed, every value
+// other than an instance, a list or a map is immutable
+func isFrozen(i *Interpreter, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case *Instance:
+		return v.IsFrozen(), nil
+	case *List:
+		return v.IsFrozen(), nil
+	case *Map:
+		return v.IsFrozen(), nil
+	}
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+// Run with -race, a task sets a field while the instance is frozen
+func TestFreezeSharedInstance(t *testing.T) {
+	source := `
+struct Box {
+  construct() { this.n = 0; }
+}
+var box = Box();
+def fill() {
+  var k = 0;
+  while (k < 1000) {
+    box.n = k;
+    k = k + 1;
+  }
+}
+var task = spawn fill();
+freeze(box);
+var frozen = isFrozen(box);
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frozen := global(t, execution, "frozen"); frozen != true {
+		t.Errorf("got isFrozen %v", frozen)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{"var l = freeze([1]); var result = isFrozen(l);", true},
+		{`var m = freeze({"a": 1}); var result = isFrozen(m);`, true},
+		{"var result = isFrozen([1]);", false},
+		{"var result = isFrozen(1);", true},
+	}
+	for _, test := range tests {
+		execution, err := run(t, test.source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := global(t, execution, "result"); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.source, got, test.want)
+		}
+	}
+
+	for _, source := range []string{
+		"var l = freeze([1]); l[0] = 2;",
+		`var m = freeze({"a": 1}); m["a"] = 2;`,
+		"struct P { construct() { this.x = 1; } } var p = freeze(P()); p.x = 2;",
+	} {
+		if _, err := run(t, source); err == nil {
+			t.Errorf("%s: expected an error", source)
+		}
+	}
+}
+
+func TestInstanceEquality(t *testing.T) {
+	source := `
+struct P { construct(x) { this.x = x; } }
+var same = P(1) == P(1.0);
+var different = P(1) == P(2);
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same := global(t, execution, "same"); same != true {
+		t.Errorf("got %v for equal fields", same)
+	}
+	if different := global(t, execution, "different"); different != false {
+		t.Errorf("got %v for different fields", different)
+	}
+}
+
+func TestFreezeErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"var l = freeze([1]); l[0] = 2;", "Can not change a frozen list"},
+		{`var m = freeze({"a": 1}); m["b"] = 2;`, "Can not change a frozen map"},
+		{"struct P { construct() { this.x = 1; } } var p = freeze(P()); p.y = 2;", "Can not set field of a frozen instance of P"},
+		// Setters and methods can not change a frozen instance either
+		{`
+struct P {
+  construct() { this.x = 1; }
+  set x2(v) { this.x = v; }
+  reset() { this.x = 0; }
+}
+var p = freeze(P());
+p.x2 = 2;`, "Can not set field of a frozen instance of P"},
+		{"struct P { construct() { this.x = 1; } reset() { this.x = 0; } } freeze(P()).reset();", "frozen instance"},
+		{"var l = freeze([1, 2]); var a = 0; a, l[0] = [1, 2];", "Can not change a frozen list"},
+		{"math.shuffle(freeze([1, 2]));", "math.shuffle can not change a frozen list"},
+		{"freeze([1][0]);", "freeze expects an instance, a list or a map but got 1"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestFreezeIsShallow(t *testing.T) {
+	execution, err := run(t, `
+const config = freeze({"limits": [1, 2]});
+config["limits"][0] = 10;
+var first = config["limits"][0];
+var inner = isFrozen(config["limits"]);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "first"); got != int64(10) {
+		t.Errorf("got first %v", got)
+	}
+	if got := global(t, execution, "inner"); got != false {
+		t.Errorf("got inner %v", got)
+	}
+}
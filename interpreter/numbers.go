@@ -0,0 +1,308 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles arithmetic on numbers. A number in runtime
+// is either an integer or a float64. Integers are int64 and are
+// promoted to a *big.Int when an operation overflows, a
+// *big.Int that fits in an int64 again is always demoted, so
+// every integer has exactly one representation. Mixing an
+// integer with a float gives a float
+
+// IsInteger checks if an object is an integer
+func (i *Interpreter) IsInteger(object interface{}) bool {
+	switch object.(type) {
+	case int64, *big.Int:
+		return true
+	}
+	return false
+}
+
+// toBig converts an integer to a *big.Int
+func toBig(value interface{}) *big.Int {
+	switch v := value.(type) {
+	case int64:
+		return big.NewInt(v)
+	case *big.Int:
+		return v
+	}
+	return nil
+}
+
+// toFloat converts a number to a float64
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	}
+	return math.NaN()
+}
+
+// normalizeBig demotes a *big.Int to an int64 when it fits
+func normalizeBig(value *big.Int) interface{} {
+	if value.IsInt64() {
+		return value.Int64()
+	}
+	return value
+}
+
+// formatFloat writes a float without an exponent unless
+// the number is very large or very small
+func formatFloat(value float64) string {
+	abs := math.Abs(value)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// NumberBinary applies an arithmetic or comparison operator
+// to two numbers
+func (i *Interpreter) NumberBinary(operator token.Token, left, right interface{}) (interface{}, error) {
+	switch operator.Type {
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		return i.IntegerBinary(operator, left, right)
+	case token.STAR_STAR:
+		return i.Power(operator, left, right)
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		// NaN is not ordered against any number
+		if math.IsNaN(toFloat(left)) || math.IsNaN(toFloat(right)) {
+			return false, nil
+		}
+		return compareResult(operator.Type, compareNumbers(left, right)), nil
+	case token.SLASH:
+		if toFloat(right) == 0 {
+			return nil, i.RuntimeError(operator, "Division by zero")
+		}
+		return toFloat(left) / toFloat(right), nil
+	}
+
+	if !i.IsInteger(left) || !i.IsInteger(right) {
+		leftNum, rightNum := toFloat(left), toFloat(right)
+		switch operator.Type {
+		case token.MINUS:
+			return leftNum - rightNum, nil
+		case token.STAR:
+			return leftNum * rightNum, nil
+		case token.PLUS:
+			return leftNum + rightNum, nil
+		case token.SLASH_SLASH:
+			if rightNum == 0 {
+				return nil, i.RuntimeError(operator, "Division by zero")
+			}
+			return math.Floor(leftNum / rightNum), nil
+		}
+		return nil, i.RuntimeError(operator, "Unknown operator")
+	}
+
+	if a, ok := left.(int64); ok {
+		if b, ok := right.(int64); ok {
+			if result, ok := int64Binary(operator.Type, a, b); ok {
+				return result, nil
+			}
+		}
+	}
+
+	a, b := toBig(left), toBig(right)
+	switch operator.Type {
+	case token.MINUS:
+		return normalizeBig(new(big.Int).Sub(a, b)), nil
+	case token.STAR:
+		return normalizeBig(new(big.Int).Mul(a, b)), nil
+	case token.PLUS:
+		return normalizeBig(new(big.Int).Add(a, b)), nil
+	case token.SLASH_SLASH:
+		if b.Sign() == 0 {
+			return nil, i.RuntimeError(operator, "Division by zero")
+		}
+		quotient, remainder := new(big.Int).QuoRem(a, b, new(big.Int))
+		if remainder.Sign() != 0 && remainder.Sign() != b.Sign() {
+			quotient.Sub(quotient, big.NewInt(1))
+		}
+		return normalizeBig(quotient), nil
+	}
+
+	return nil, i.RuntimeError(operator, "Unknown operator")
+}
+
+// int64Binary applies an operator to two int64 values,
+// it reports false when the result overflows or the
+// operation needs to be handled by math/big
+func int64Binary(operator token.TokenType, a, b int64) (int64, bool) {
+	switch operator {
+	case token.PLUS:
+		result := a + b
+		if (a > 0 && b > 0 && result < 0) || (a < 0 && b < 0 && result >= 0) {
+			return 0, false
+		}
+		return result, true
+	case token.MINUS:
+		result := a - b
+		if (a >= 0 && b < 0 && result < 0) || (a < 0 && b > 0 && result >= 0) {
+			return 0, false
+		}
+		return result, true
+	case token.STAR:
+		if a == 0 || b == 0 {
+			return 0, true
+		}
+		result := a * b
+		if result/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+			return 0, false
+		}
+		return result, true
+	case token.SLASH_SLASH:
+		if b == 0 || (a == math.MinInt64 && b == -1) {
+			return 0, false
+		}
+		quotient := a / b
+		if a%b != 0 && (a < 0) != (b < 0) {
+			quotient--
+		}
+		return quotient, true
+	}
+	return 0, false
+}
+
+// NegateNumber negates a number
+func (i *Interpreter) NegateNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		if v == math.MinInt64 {
+			return new(big.Int).Neg(big.NewInt(v))
+		}
+		return -v
+	case *big.Int:
+		return normalizeBig(new(big.Int).Neg(v))
+	}
+	return -toFloat(value)
+}
+
+// compareNumbers compares two numbers and returns -1, 0 or +1.
+// Integers are compared exactly, anything else as float64
+func compareNumbers(left, right interface{}) int {
+	if a, ok := left.(int64); ok {
+		if b, ok := right.(int64); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	if toBig(left) != nil && toBig(right) != nil {
+		return toBig(left).Cmp(toBig(right))
+	}
+
+	a, b := toFloat(left), toFloat(right)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareResult turns the result of a comparison into the
+// value of a comparison operator
+func compareResult(operator token.TokenType, cmp int) bool {
+	switch operator {
+	case token.GREATER:
+		return cmp > 0
+	case token.GREATER_EQUAL:
+		return cmp >= 0
+	case token.LESS:
+		return cmp < 0
+	case token.LESS_EQUAL:
+		return cmp <= 0
+	}
+	return false
+}
+
+// MAX_INTEGER_BITS bounds the size of the integers that shifts
+// and exponentiation are allowed to produce
+const MAX_INTEGER_BITS int = 1 << 24
+
+// IntegerBinary applies a bitwise or shift operator, these
+// are only defined on integers
+func (i *Interpreter) IntegerBinary(operator token.Token, left, right interface{}) (interface{}, error) {
+	if !i.IsInteger(left) || !i.IsInteger(right) {
+		return nil, i.RuntimeError(operator, "Operands of '"+operator.Lexeme+"' must be integers")
+	}
+
+	a, b := toBig(left), toBig(right)
+	switch operator.Type {
+	case token.AMPERSAND:
+		return normalizeBig(new(big.Int).And(a, b)), nil
+	case token.PIPE:
+		return normalizeBig(new(big.Int).Or(a, b)), nil
+	case token.CARET:
+		return normalizeBig(new(big.Int).Xor(a, b)), nil
+	}
+
+	if b.Sign() < 0 {
+		return nil, i.RuntimeError(operator, "Shift count must not be negative")
+	}
+	if operator.Type == token.GREATER_GREATER {
+		if !b.IsInt64() || b.Int64() > int64(a.BitLen()) {
+			// Shifting every bit out leaves only the sign
+			if a.Sign() < 0 {
+				return int64(-1), nil
+			}
+			return int64(0), nil
+		}
+		return normalizeBig(new(big.Int).Rsh(a, uint(b.Int64()))), nil
+	}
+
+	if !b.IsInt64() || a.BitLen()+int(b.Int64()) > MAX_INTEGER_BITS {
+		return nil, i.RuntimeError(operator, "Shift count is too large")
+	}
+	return normalizeBig(new(big.Int).Lsh(a, uint(b.Int64()))), nil
+}
+
+// Power raises a number to a power. An integer raised to a non
+// negative integer is an integer, anything else is a float
+func (i *Interpreter) Power(operator token.Token, left, right interface{}) (interface{}, error) {
+	if !i.IsInteger(left) || !i.IsInteger(right) || toBig(right).Sign() < 0 {
+		return math.Pow(toFloat(left), toFloat(right)), nil
+	}
+
+	base, exponent := toBig(left), toBig(right)
+	if base.BitLen() > 1 && (!exponent.IsInt64() || int64(base.BitLen())*exponent.Int64() > int64(MAX_INTEGER_BITS)) {
+		return nil, i.RuntimeError(operator, "Result of '**' is too large")
+	}
+	if !exponent.IsInt64() {
+		// The base is -1, 0 or 1 here
+		if base.Sign() < 0 && exponent.Bit(0) == 1 {
+			return int64(-1), nil
+		}
+		if base.Sign() == 0 {
+			return int64(0), nil
+		}
+		return int64(1), nil
+	}
+	return normalizeBig(new(big.Int).Exp(base, exponent, nil)), nil
+}
+
+// BitwiseNot inverts the bits of an integer
+func (i *Interpreter) BitwiseNot(value interface{}) interface{} {
+	if v, ok := value.(int64); ok {
+		return ^v
+	}
+	return normalizeBig(new(big.Int).Not(toBig(value)))
+}
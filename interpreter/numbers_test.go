@@ -0,0 +1,155 @@
+package interpreter_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/lang"
+)
+
+// run compiles and runs a source, returning the execution so
+// its globals can be read
+func run(t *testing.T, source string) (*interpreter.Interpreter, error) {
+	t.Helper()
+	program, err := lang.Compile(source)
+	if err != nil {
+		t.Fatalf("compiling %q: %v", source, err)
+	}
+	execution := interpreter.NewExecution(program)
+	return execution, program.Execute(execution)
+}
+
+// evaluate gives the value of an expression
+func evaluate(t *testing.T, expression string) interface{} {
+	t.Helper()
+	execution, err := run(t, "var result = "+expression+";")
+	if err != nil {
+		t.Fatalf("evaluating %s: %v", expression, err)
+	}
+	return global(t, execution, "result")
+}
+
+// evaluateError gives the error of evaluating an expression
+func evaluateError(t *testing.T, expression string) error {
+	t.Helper()
+	_, err := run(t, "var result = "+expression+";")
+	if err == nil {
+		t.Fatalf("evaluating %s: expected an error", expression)
+	}
+	return err
+}
+
+func TestIntegerArithmetic(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{"1 + 2", int64(3)},
+		{"7//2", int64(3)},
+		{"-7//2", int64(-4)},
+		{"7 / 2", 3.5},
+		{"6 / 3", 2.0},
+		{"7.5//2", 3.0},
+		{"1 + 2.5", 3.5},
+		{"2 ** 10", int64(1024)},
+		{"2 ** -1", 0.5},
+		{"0xFF + 0b1 + 0o7", int64(263)},
+		// Demoted again once it fits
+		{"(9223372036854775807 + 1) - 1", int64(9223372036854775807)},
+		{"-9223372036854775808//-1 - 1", int64(9223372036854775807)},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %#v, want %#v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestIntegerPromotion(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775808 - 1", "-9223372036854775809"},
+		{"4294967296 * 4294967296", "18446744073709551616"},
+		{"2 ** 100", "1267650600228229401496703205376"},
+		{"1 << 70", "1180591620717411303424"},
+	}
+	for _, test := range tests {
+		got, ok := evaluate(t, test.expression).(*big.Int)
+		if !ok || got.String() != test.want {
+			t.Errorf("%s: got %v, want %s", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"1//0", "Division by zero"},
+		{"2 ** 100000000", "too large"},
+		{"1 << 100000000", "too large"},
+		{"1.5//0", "Division by zero"},
+	}
+	for _, test := range tests {
+		if err := evaluateError(t, test.expression); !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.expression, err, test.want)
+		}
+	}
+}
+
+func TestBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{"12 & 10", int64(8)},
+		{"12 | 10", int64(14)},
+		{"12 ^ 10", int64(6)},
+		{"~0", int64(-1)},
+		{"~5", int64(-6)},
+		{"1 << 4", int64(16)},
+		{"256 >> 4", int64(16)},
+		{"-16 >> 2", int64(-4)},
+		{"-1 >> 100", int64(-1)},
+		{"1 >> 100", int64(0)},
+		{"(1 << 70) >> 69", int64(2)},
+		{"(1 << 64) & 1", int64(0)},
+		// Shifts bind tighter than the bitwise operators, which
+		// bind tighter than the comparisons
+		{"1 | 2 & 3", int64(3)},
+		{"1 << 2 + 1", int64(8)},
+		{"5 & 3 == 1", true},
+		{"2 ** 3 ** 2", int64(512)},
+		{"-2 ** 2", int64(-4)},
+		{"2 ** 0.5 == 2 ** 0.5", true},
+		{"(-1) ** 10000000000000000000000", int64(1)},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %#v, want %#v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestBitwiseErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"[1.5][0] & 1", "Operands of '&' must be integers"},
+		{"1 | [2.0][0]", "Operands of '|' must be integers"},
+		{"1 << -1", "Shift count must not be negative"},
+		{"~[1.5][0]", "Operand of '~' must be an integer"},
+	}
+	for _, test := range tests {
+		if err := evaluateError(t, test.expression); !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.expression, err, test.want)
+		}
+	}
+}
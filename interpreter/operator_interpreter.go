@@ -0,0 +1,93 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// Special methods a struct can define to give meaning to the
+// operators applied to its instances
+const (
+	ADD_METHOD       string = "__add__"
+	SUB_METHOD       string = "__sub__"
+	MUL_METHOD       string = "__mul__"
+	DIV_METHOD       string = "__div__"
+	EQ_METHOD        string = "__eq__"
+	LT_METHOD        string = "__lt__"
+	LE_METHOD        string = "__le__"
+	INDEX_METHOD     string = "__index__"
+	SET_INDEX_METHOD string = "__setindex__"
+	STR_METHOD       string = "__str__"
+)
+
+// operatorMethods maps an operator to the method it calls
+var operatorMethods = map[token.TokenType]string{
+	token.PLUS:          ADD_METHOD,
+	token.MINUS:         SUB_METHOD,
+	token.STAR:          MUL_METHOD,
+	token.SLASH:         DIV_METHOD,
+	token.EQUAL_EQUAL:   EQ_METHOD,
+	token.BANG_EQUAL:    EQ_METHOD,
+	token.LESS:          LT_METHOD,
+	token.LESS_EQUAL:    LE_METHOD,
+	token.GREATER:       LE_METHOD,
+	token.GREATER_EQUAL: LT_METHOD,
+}
+
+// OperatorMethod gives the name of the method called for an
+// operator with an instance on its left
+func OperatorMethod(operator token.TokenType) (string, bool) {
+	name, ok := operatorMethods[operator]
+	return name, ok
+}
+
+// CallMethod calls a method of an instance
+func (i *Interpreter) CallMethod(instance *Instance, name string, arguments []interface{}) (interface{}, error) {
+	method, ok := instance.ClassName.Methods[name]
+	if !ok {
+		return nil, fmt.Errorf("%s does not define %s", instance.ClassName.Name, name)
+	}
+	return method.Bind(instance).Call(i, arguments)
+}
+
+// InstanceBinary applies an operator with an instance on its
+// left by calling the matching special method. != negates
+// __eq__, > negates __le__ and >= negates __lt__
+func (i *Interpreter) InstanceBinary(operator token.Token, left *Instance, right interface{}) (interface{}, error) {
+	name, ok := OperatorMethod(operator.Type)
+	if !ok {
+		return nil, i.RuntimeError(operator, "Operator can not be applied to an instance of "+left.ClassName.Name)
+	}
+
+	method, ok := left.ClassName.Methods[name]
+	if !ok {
+		// Equality is defined for every instance
+		switch operator.Type {
+		case token.EQUAL_EQUAL, token.BANG_EQUAL:
+			equal, err := i.IsEqual(left, right)
+			if err != nil {
+				return nil, err
+			}
+			return equal == (operator.Type == token.EQUAL_EQUAL), nil
+		}
+		return nil, i.RuntimeError(operator, fmt.Sprintf("%s does not define %s for '%s'", left.ClassName.Name, name, operator.Lexeme))
+	}
+
+	arguments := []interface{}{right}
+	if err := i.CheckArity(operator, method, len(arguments), false); err != nil {
+		return nil, err
+	}
+	result, err := method.Bind(left).Call(i, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator.Type {
+	case token.BANG_EQUAL, token.GREATER, token.GREATER_EQUAL:
+		return !i.IsTruthy(result), nil
+	case token.EQUAL_EQUAL, token.LESS, token.LESS_EQUAL:
+		return i.IsTruthy(result), nil
+	}
+	return result, nil
+}
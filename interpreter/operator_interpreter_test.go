@@ -0,0 +1,98 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+)
+
+func TestEqualityMethodErrors(t *testing.T) {
+	source := `
+struct S {
+  construct() {}
+  __eq__(other) { return [1][3]; }
+}
+var equal = [S()] == [S()];
+`
+	_, err := run(t, source)
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("expected the error of __eq__ but got %v", err)
+	}
+}
+
+func TestExitInEqualityMethod(t *testing.T) {
+	source := `
+struct S {
+  construct() {}
+  __eq__(other) { exit(7); }
+}
+var equal = {"a": S()} != {"a": S()};
+var after = true;
+`
+	execution, err := run(t, source)
+	exit, ok := err.(*interpreter.ExitSignal)
+	if !ok || exit.Code != 7 {
+		t.Fatalf("expected exit status 7 but got %v", err)
+	}
+	if _, err := execution.Globals.Get(nameToken("after")); err == nil {
+		t.Error("the program continued after exit")
+	}
+}
+
+func TestEqualityMethod(t *testing.T) {
+	source := `
+struct Money {
+  construct(cents) { this.cents = cents; }
+  __eq__(other) { return this.cents == other.cents; }
+}
+var equal = [Money(5)] == [Money(5)];
+var different = Money(5) != Money(6);
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"equal", "different"} {
+		if value := global(t, execution, name); value != true {
+			t.Errorf("got %s %v", name, value)
+		}
+	}
+}
+
+func TestValuesContainingThemselves(t *testing.T) {
+	source := `
+var l = [1];
+l[0] = l;
+var m = {"a": 1};
+m["self"] = m;
+var other = [1];
+other[0] = other;
+var same = l == l;
+var cyclic = l == other;
+var different = l == [[2]];
+var list = "{}".format(l);
+var map = "{}".format(m);
+var nested = "{}".format([l, l]);
+`
+	execution, err := run(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"same", true},
+		{"cyclic", true},
+		{"different", false},
+		{"list", "[[...]]"},
+		{"map", `{"a": 1, "self": {...}}`},
+		{"nested", "[[[...]], [[...]]]"},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
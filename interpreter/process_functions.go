@@ -0,0 +1,148 @@
+package interpreter
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// This file handles the natives giving scripts access to their
+// arguments, the environment variables and other processes.
+// exec gives a map of the stdout, stderr and exit code of a
+// command and exit stops the program from anywhere, the
+// interpreter exiting with its status
+
+// ExitSignal unwinds the program when exit is called, it is
+// returned by Interpret with the status the program exits with
+type ExitSignal struct {
+	Code int
+}
+
+var _ error = (*ExitSignal)(nil)
+
+func (e *ExitSignal) Error() string {
+	return "Exit with status " + strconv.Itoa(e.Code)
+}
+
+// DefineProcessFunctions defines the natives for the arguments,
+// the environment variables and running other processes
+func (i *Interpreter) DefineProcessFunctions() {
+	i.Define(i.Globals, NewNativeFunction("args", 0, processArgs), "args")
+	i.Define(i.Globals, NewNativeFunction("env", 1, processEnv), "env")
+	i.Define(i.Globals, NewNativeFunction("setEnv", 2, processSetEnv), "setEnv")
+	i.Define(i.Globals, NewVariadicFunction("exit", 0, 1, processExit), "exit")
+	i.Define(i.Globals, NewVariadicFunction("exec", 1, 2, processExec), "exec")
+}
+
+// CheckProcess returns an error when the host has disabled the
+// natives for the environment variables and other processes
+func (i *Interpreter) CheckProcess(function string) error {
+	if !i.Host.Process {
+		return errors.New(function + " is disabled by the host")
+	}
+	return nil
+}
+
+// processArgs gives the arguments the host passed to the script
+func processArgs(i *Interpreter, args []interface{}) (interface{}, error) {
+	elements := make([]interface{}, len(i.Host.Args))
+	for index, arg := range i.Host.Args {
+		elements[index] = arg
+	}
+	return NewList(elements), nil
+}
+
+// processEnv gives the value of an environment variable, nil
+// when it is not set
+func processEnv(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckProcess("env"); err != nil {
+		return nil, err
+	}
+	name, err := stringArg("env", args[0])
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	return nil, nil
+}
+
+func processSetEnv(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckProcess("setEnv"); err != nil {
+		return nil, err
+	}
+	name, err := stringArg("setEnv", args[0])
+	if err != nil {
+		return nil, err
+	}
+	value, err := stringArg("setEnv", args[1])
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Setenv(name, value); err != nil {
+		return nil, errors.New("setEnv: " + err.Error())
+	}
+	return nil, nil
+}
+
+// processExit stops the program with a status, 0 by default
+func processExit(i *Interpreter, args []interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, &ExitSignal{Code: 0}
+	}
+	code, ok := args[0].(int64)
+	if !ok || code < 0 || code > 255 {
+		return nil, errors.New("exit expects a status from 0 to 255 but got " + i.stringify(args[0]))
+	}
+	return nil, &ExitSignal{Code: int(code)}
+}
+
+// processExec runs a command with a list of arguments and waits
+// for it, giving a map of its stdout, stderr and exit code. A
+// command failing with a status is not an error
+func processExec(i *Interpreter, args []interface{}) (interface{}, error) {
+	if err := i.CheckProcess("exec"); err != nil {
+		return nil, err
+	}
+	name, err := stringArg("exec", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments []string
+	if len(args) == 2 {
+		list, ok := args[1].(*List)
+		if !ok {
+			return nil, errors.New("exec expects a list of arguments but got " + i.stringify(args[1]))
+		}
+		for _, element := range list.Elements {
+			argument, err := stringArg("exec", element)
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, argument)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	command := exec.Command(name, arguments...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	code := int64(0)
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, errors.New("exec: " + err.Error())
+		}
+		code = int64(exitErr.ExitCode())
+	}
+
+	result := NewMap()
+	result.Set("stdout", stdout.String())
+	result.Set("stderr", stderr.String())
+	result.Set("code", code)
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+)
+
+func TestExit(t *testing.T) {
+	tests := []struct {
+		source string
+		code   int
+	}{
+		{"exit();", 0},
+		{"exit(2); var after = true;", 2},
+		// A task that is never joined
+		{"def f() { exit(3); } spawn f(); await sleep(50); var after = true;", 3},
+		{"async def f() { await sleep(1); exit(4); } f(); await sleep(50); var after = true;", 4},
+		// The program is blocked when the task calls exit
+		{"def f() { exit(5); } var ch = channel(); spawn f(); ch.recv(); var after = true;", 5},
+		{"def f() { exit(6); } var ch = channel(); spawn f(); ch.send(1); var after = true;", 6},
+		{"def f() { exit(7); } var ch = channel(); spawn f(); select { var v = ch.recv() => println v; } var after = true;", 7},
+		{"def wait(ch) { ch.recv(); } def f() { exit(8); } var ch = channel(); var t = spawn wait(ch); spawn f(); t.join(); var after = true;", 8},
+		{"def f() { exit(9); } var ch = channel(); spawn f(); for (var v in ch) { println v; } var after = true;", 9},
+		// A busy loop stops as well
+		{"def f() { exit(10); } spawn f(); var n = 0; while (true) { n = n + 1; } var after = true;", 10},
+	}
+	for _, test := range tests {
+		execution, err := run(t, test.source)
+		exit, ok := err.(*interpreter.ExitSignal)
+		if !ok || exit.Code != test.code {
+			t.Errorf("%s: expected exit status %d but got %v", test.source, test.code, err)
+			continue
+		}
+		if _, err := execution.Globals.Get(nameToken("after")); err == nil {
+			t.Errorf("%s: the program continued after exit", test.source)
+		}
+	}
+}
+
+func TestExitStatusRange(t *testing.T) {
+	if _, err := run(t, "exit(256);"); err == nil {
+		t.Error("expected an error for status 256")
+	} else if _, ok := err.(*interpreter.ExitSignal); ok {
+		t.Error("status 256 exited")
+	}
+}
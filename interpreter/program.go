@@ -0,0 +1,48 @@
+package interpreter
+
+import "github.com/Atul-Ranjan12/parser/expressions"
+
+// Program is a compiled program, its statements and the scope
+// depths the resolver found for its variables. A program is
+// never changed once compiled so any number of executions can
+// run it at the same time
+type Program struct {
+	statements []expressions.Stmt
+	locals     map[expressions.Expr]int
+	// Warnings are the warnings of the resolver
+	Warnings []string
+}
+
+// NewProgram creates a program from resolved and checked
+// statements and the scope depths of their variables
+func NewProgram(statements []expressions.Stmt, locals map[expressions.Expr]int, warnings []string) *Program {
+	return &Program{
+		statements: statements,
+		locals:     locals,
+		Warnings:   warnings,
+	}
+}
+
+// Statements returns the top level statements of the program,
+// changing the list does not change the program
+func (p *Program) Statements() []expressions.Stmt {
+	return append([]expressions.Stmt(nil), p.statements...)
+}
+
+// NewExecution creates an interpreter to run a compiled program.
+// Each execution has its own globals and event loop, values a
+// host defines in its globals are only seen by that execution
+func NewExecution(program *Program) *Interpreter {
+	return newInterpreter(program.locals)
+}
+
+// Execute runs the program on an execution created for it by
+// NewExecution
+func (p *Program) Execute(execution *Interpreter) error {
+	return execution.Interpret(p.statements)
+}
+
+// Run runs the program on a new execution
+func (p *Program) Run() error {
+	return p.Execute(NewExecution(p))
+}
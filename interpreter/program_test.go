@@ -0,0 +1,113 @@
+package interpreter_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Atul-Ranjan12/interpreter"
+	"github.com/Atul-Ranjan12/lang"
+)
+
+func TestParallelExecutions(t *testing.T) {
+	source := `
+struct Counter {
+  construct(start) { this.count = start; }
+  add(n) { this.count = this.count + n; return this; }
+}
+def numbers(n) {
+  var i = 0;
+  while (i < n) { yield i; i = i + 1; }
+}
+async def twice(x) {
+  await sleep(1);
+  return x * 2;
+}
+var counter = Counter(seed);
+for (var n in numbers(100)) {
+  counter.add(n);
+}
+var total = await twice(counter.count);
+`
+	program, err := lang.Compile(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wait sync.WaitGroup
+	for seed := 0; seed < 8; seed++ {
+		wait.Add(1)
+		go func(seed int64) {
+			defer wait.Done()
+			execution := interpreter.NewExecution(program)
+			execution.Globals.Define("seed", seed)
+			if err := program.Execute(execution); err != nil {
+				t.Error(err)
+				return
+			}
+			value, err := execution.Globals.Get(nameToken("total"))
+			if want := (seed + 4950) * 2; err != nil || value != want {
+				t.Errorf("seed %d: got %v, want %d", seed, value, want)
+			}
+		}(int64(seed))
+	}
+	wait.Wait()
+
+	// Globals defined by one execution are not seen by another
+	execution := interpreter.NewExecution(program)
+	if err := program.Execute(execution); err == nil || !strings.Contains(err.Error(), "seed") {
+		t.Errorf("expected seed to be undefined but got %v", err)
+	}
+}
+
+func TestProgramIsReusable(t *testing.T) {
+	program, err := lang.Compile(`
+var runs = 0;
+runs = runs + 1;
+struct Box { construct() { this.n = 0; } }
+var box = Box();
+box.n = box.n + 1;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// State changed by one execution does not carry over
+	for index := 0; index < 3; index++ {
+		execution := interpreter.NewExecution(program)
+		if err := program.Execute(execution); err != nil {
+			t.Fatal(err)
+		}
+		if got := global(t, execution, "runs"); got != int64(1) {
+			t.Errorf("run %d: got runs %v", index, got)
+		}
+	}
+
+	statements := program.Statements()
+	statements[0] = nil
+	if program.Statements()[0] == nil {
+		t.Error("changing the statements changed the program")
+	}
+	if err := program.Run(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProgramExit(t *testing.T) {
+	program, err := lang.Compile("exit(3);")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exit, ok := program.Run().(*interpreter.ExitSignal)
+	if !ok || exit.Code != 3 {
+		t.Errorf("expected exit status 3 but got %v", exit)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, source := range []string{"var = 1;", "return 1;", `var x: int = "a";`} {
+		if _, err := lang.Compile(source); err == nil {
+			t.Errorf("%s: expected an error", source)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles the re module compiling regular expressions
+// in the syntax of Go. A match is a map of its text, the start
+// and end of it in characters, its groups and its named groups,
+// a group that did not match being nil. replace takes a string,
+// where $1 or ${name} is a group, or a function called with
+// each match
+
+// Regex is a compiled regular expression
+type Regex struct {
+	Pattern string
+	regexp  *regexp.Regexp
+}
+
+func (r *Regex) String() string {
+	return "<regex " + r.Pattern + ">"
+}
+
+// DefineReModule defines the re module
+func (i *Interpreter) DefineReModule() {
+	module := NewModule("re")
+	module.Function("compile", 1, 1, reCompile)
+	module.Function("escape", 1, 1, reEscape)
+	i.Globals.Define(module.Name, module)
+}
+
+// reCompile compiles a pattern, an invalid pattern is an error
+// naming the line of the call
+func reCompile(i *Interpreter, args []interface{}) (interface{}, error) {
+	pattern, err := stringArg("re.compile", args[0])
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		line := 0
+		if len(i.Frames) > 0 {
+			line = i.Frames[len(i.Frames)-1].Line
+		}
+		return nil, fmt.Errorf("re.compile at line %d: %s", line, err)
+	}
+	return &Regex{Pattern: pattern, regexp: compiled}, nil
+}
+
+// reEscape escapes the characters of a text that have a meaning
+// in patterns
+func reEscape(i *Interpreter, args []interface{}) (interface{}, error) {
+	text, err := stringArg("re.escape", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return regexp.QuoteMeta(text), nil
+}
+
+// countArg checks the optional limit of the matches of findAll
+// and split, a negative limit means no limit
+func countArg(i *Interpreter, method string, args []interface{}, index int) (int, error) {
+	if len(args) <= index || args[index] == nil {
+		return -1, nil
+	}
+	return intArg(i, method, args[index])
+}
+
+// newMatch converts the byte offsets of a match and its groups
+// given by regexp to a map
+func (r *Regex) newMatch(text string, offsets []int) *Map {
+	// Offsets of the text are in bytes but strings are
+	// indexed by characters
+	start := int64(utf8.RuneCountInString(text[:offsets[0]]))
+	end := start + int64(utf8.RuneCountInString(text[offsets[0]:offsets[1]]))
+
+	groups := make([]interface{}, 0, len(offsets)/2-1)
+	named := NewMap()
+	for group := 1; group < len(offsets)/2; group++ {
+		// A group that did not take part in the match is nil
+		var value interface{}
+		if offsets[2*group] >= 0 {
+			value = text[offsets[2*group]:offsets[2*group+1]]
+		}
+		groups = append(groups, value)
+		if name := r.regexp.SubexpNames()[group]; name != "" {
+			named.Set(name, value)
+		}
+	}
+
+	match := NewMap()
+	match.Set("text", text[offsets[0]:offsets[1]])
+	match.Set("start", start)
+	match.Set("end", end)
+	match.Set("groups", NewList(groups))
+	match.Set("named", named)
+	return match
+}
+
+// replaceFunction replaces every match by the string returned by
+// a function called with the match
+func (r *Regex) replaceFunction(i *Interpreter, text string, function Callable) (interface{}, error) {
+	var result []byte
+	last := 0
+	for _, offsets := range r.regexp.FindAllStringSubmatchIndex(text, -1) {
+		replacement, err := function.Call(i, []interface{}{r.newMatch(text, offsets)})
+		if err != nil {
+			return nil, err
+		}
+		s, ok := replacement.(string)
+		if !ok {
+			return nil, errors.New("replace expects the function to return a string but got " + i.stringify(replacement))
+		}
+		result = append(result, text[last:offsets[0]]...)
+		result = append(result, s...)
+		last = offsets[1]
+	}
+	return string(append(result, text[last:]...)), nil
+}
+
+// GetRegexMethod returns a property of a regex, its pattern or
+// one of its methods
+func (i *Interpreter) GetRegexMethod(r *Regex, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "pattern":
+		return r.Pattern, nil
+	case "match":
+		// match checks if the regex matches anywhere in a text
+		return NewNativeFunction("match", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			text, err := stringArg("match", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return r.regexp.MatchString(text), nil
+		}), nil
+	case "find":
+		// find gives the first match, nil when there is none
+		return NewNativeFunction("find", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			text, err := stringArg("find", args[0])
+			if err != nil {
+				return nil, err
+			}
+			offsets := r.regexp.FindStringSubmatchIndex(text)
+			if offsets == nil {
+				return nil, nil
+			}
+			return r.newMatch(text, offsets), nil
+		}), nil
+	case "findAll":
+		// findAll gives a list of the matches, at most as many
+		// as the optional limit
+		return NewVariadicFunction("findAll", 1, 2, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			text, err := stringArg("findAll", args[0])
+			if err != nil {
+				return nil, err
+			}
+			limit, err := countArg(i, "findAll", args, 1)
+			if err != nil {
+				return nil, err
+			}
+			matches := make([]interface{}, 0)
+			for _, offsets := range r.regexp.FindAllStringSubmatchIndex(text, limit) {
+				matches = append(matches, r.newMatch(text, offsets))
+			}
+			return NewList(matches), nil
+		}), nil
+	case "replace":
+		// replace replaces every match by a string, where $1 or
+		// ${name} is a group, or by the result of a function
+		// called with the match
+		return NewNativeFunction("replace", 2, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			text, err := stringArg("replace", args[0])
+			if err != nil {
+				return nil, err
+			}
+			switch replacement := args[1].(type) {
+			case string:
+				return r.regexp.ReplaceAllString(text, replacement), nil
+			case Callable:
+				if !accepts(replacement, 1) {
+					return nil, errors.New("replace expects a function of one parameter but got " + callableName(replacement))
+				}
+				return r.replaceFunction(i, text, replacement)
+			}
+			return nil, errors.New("replace expects a string or a function but got " + i.stringify(args[1]))
+		}), nil
+	case "split":
+		// split gives the parts of a text between the matches,
+		// at most as many as the optional limit
+		return NewVariadicFunction("split", 1, 2, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			text, err := stringArg("split", args[0])
+			if err != nil {
+				return nil, err
+			}
+			limit, err := countArg(i, "split", args, 1)
+			if err != nil {
+				return nil, err
+			}
+			parts := r.regexp.Split(text, limit)
+			elements := make([]interface{}, len(parts))
+			for index, part := range parts {
+				elements[index] = part
+			}
+			return NewList(elements), nil
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Regexes have no property "+name.Lexeme)
+}
@@ -0,0 +1,76 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegex(t *testing.T) {
+	execution, err := run(t, `
+var r = re.compile("(?P<word>[a-zé]+)(\d)?");
+var pattern = r.pattern;
+var matches = r.match("x1");
+var noMatch = r.match("123");
+var found = "{}".format(r.find("12 héllo3 ab"));
+var notFound = r.find("123");
+var all = len(r.findAll("ab1 cd ef2", -1));
+var limited = len(r.findAll("ab1 cd ef2", 2));
+var unmatchedGroup = r.findAll("ab1 cd", -1)[1]["groups"][1];
+def up(m) { return m["named"]["word"].upper(); }
+var replacedByFunction = r.replace("ab1 cd", up);
+var replacedByGroup = r.replace("ab1 cd", "<${word}>");
+var numbered = r.replace("ab1", "$2$1");
+var parts = "{}".format(re.compile(",\s*").split("a, b,c", -1));
+var limitedParts = "{}".format(re.compile(",").split("a,b,c", 2));
+var escaped = re.escape("a.b*c");
+var literal = re.compile(re.escape("a.b")).match("axb");
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"pattern", `(?P<word>[a-zé]+)(\d)?`},
+		{"matches", true},
+		{"noMatch", false},
+		// start and end count characters, not bytes
+		{"found", `{"text": "héllo3", "start": 3, "end": 9, "groups": ["héllo", "3"], "named": {"word": "héllo"}}`},
+		{"notFound", nil},
+		{"all", int64(3)},
+		{"limited", int64(2)},
+		{"unmatchedGroup", nil},
+		{"replacedByFunction", "AB CD"},
+		{"replacedByGroup", "<ab> <cd>"},
+		{"numbered", "1ab"},
+		{"parts", `["a", "b", "c"]`},
+		{"limitedParts", `["a", "b,c"]`},
+		{"escaped", `a\.b\*c`},
+		{"literal", false},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRegexErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"var a = 1;\nre.compile(\"(\");", "re.compile at line 2: "},
+		{`def f(m) { return 1; } re.compile("a").replace("a", f);`, "replace expects the function to return a string but got 1"},
+		{`def f(a, b) { return a; } re.compile("a").replace("a", f);`, "replace expects a function of one parameter but got f"},
+		{`re.compile("a").replace("a", [1][0]);`, "replace expects a string or a function but got 1"},
+		{`def f(r) { return r.nope; } f(re.compile("a"));`, "Regexes have no property nope"},
+	}
+	for _, test := range tests {
+		_, err := run(t, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
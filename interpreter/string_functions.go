@@ -0,0 +1,109 @@
+package interpreter
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// This file defines the natives that work on strings.
+// Strings are stored as UTF-8, but every length, index and
+// slice bound seen by a program is counted in code points
+
+// DefineStringFunctions defines the string natives in the
+// given environment
+func (i *Interpreter) DefineStringFunctions() {
+	i.Define(i.Globals, NewNativeFunction("len", 1, stringLen), "len")
+	i.Define(i.Globals, NewNativeFunction("charAt", 2, stringCharAt), "charAt")
+	i.Define(i.Globals, NewVariadicFunction("slice", 2, 3, stringSlice), "slice")
+}
+
+// stringLen returns the number of code points in a string,
+// or the number of elements in a list or a map
+func stringLen(i *Interpreter, args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	case *List:
+		return int64(v.Len()), nil
+	case *Map:
+		return int64(v.Len()), nil
+	}
+	return nil, fmt.Errorf("len expects a string, a list or a map but got %s", i.stringify(args[0]))
+}
+
+// stringCharAt returns the code point at an index as a string
+func stringCharAt(i *Interpreter, args []interface{}) (interface{}, error) {
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("charAt expects a string but got %s", i.stringify(args[0]))
+	}
+	index, err := toIndex(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	if index < 0 {
+		index += len(runes)
+	}
+	if index < 0 || index >= len(runes) {
+		return nil, fmt.Errorf("String index %d out of range", index)
+	}
+	return string(runes[index]), nil
+}
+
+// stringSlice returns the code points between start and end,
+// negative bounds count from the end of the string and a
+// missing or nil end slices to the end of the string
+func stringSlice(i *Interpreter, args []interface{}) (interface{}, error) {
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("slice expects a string but got %s", i.stringify(args[0]))
+	}
+
+	runes := []rune(s)
+	start, err := toIndex(args[1])
+	if err != nil {
+		return nil, err
+	}
+	end := len(runes)
+	if len(args) > 2 && args[2] != nil {
+		end, err = toIndex(args[2])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start, end = clampBound(start, len(runes)), clampBound(end, len(runes))
+	if start >= end {
+		return "", nil
+	}
+	return string(runes[start:end]), nil
+}
+
+// toIndex converts an integer value to an index
+func toIndex(value interface{}) (int, error) {
+	num, ok := value.(int64)
+	if !ok {
+		return 0, fmt.Errorf("Index must be an integer")
+	}
+	if num != int64(int(num)) {
+		return 0, fmt.Errorf("Index %d is out of range", num)
+	}
+	return int(num), nil
+}
+
+// clampBound resolves a negative bound and clamps it
+// to the range of a sequence of the given length
+func clampBound(bound, length int) int {
+	if bound < 0 {
+		bound += length
+	}
+	if bound < 0 {
+		return 0
+	}
+	if bound > length {
+		return length
+	}
+	return bound
+}
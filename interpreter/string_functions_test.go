@@ -0,0 +1,77 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringFunctionsCountCodePoints(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{`len("größe")`, int64(5)},
+		{`len("👋👋")`, int64(2)},
+		{`len("")`, int64(0)},
+		{`len([1, 2, 3])`, int64(3)},
+		{`len({"a": 1})`, int64(1)},
+		{`charAt("größe", 2)`, "ö"},
+		{`charAt("größe", -1)`, "e"},
+		{`slice("héllo", 1, 3)`, "él"},
+		{`slice("héllo", -3)`, "llo"},
+		{`slice("héllo", 1, nil)`, "éllo"},
+		{`slice("héllo", 4, 1)`, ""},
+		{`slice("héllo", -10, 10)`, "héllo"},
+		{`"größe"[3]`, "ß"},
+		{`"größe"[-1]`, "e"},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %#v, want %#v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	execution, err := run(t, `
+var größe = 3;
+def verdoppeln(zahl) { return zahl * 2; }
+var ergebnis = verdoppeln(größe);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "ergebnis"); got != int64(6) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestForInOverString(t *testing.T) {
+	execution, err := run(t, `
+var reversed = "";
+for (var c in "añb👋") { reversed = c + reversed; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "reversed"); got != "👋bña" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestStringFunctionErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{`charAt("abc", 3)`, "out of range"},
+		{`charAt([1][0], 0)`, "charAt expects a string"},
+		{`slice("abc", [1.5][0])`, "Index must be an integer"},
+		{`len([1][0])`, "len expects a string, a list or a map"},
+	}
+	for _, test := range tests {
+		if err := evaluateError(t, test.expression); !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.expression, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles method calls on string values, every
+// method is returned as a native callable bound to the string
+
+// stringMethod is the go implementation of a string method,
+// receiving the string it was called on
+type stringMethod struct {
+	minArity int
+	maxArity int
+	method   func(i *Interpreter, s string, args []interface{}) (interface{}, error)
+}
+
+var stringMethods = map[string]stringMethod{
+	"upper":      {0, 0, stringUpper},
+	"lower":      {0, 0, stringLower},
+	"trim":       {0, 0, stringTrim},
+	"split":      {1, 1, stringSplit},
+	"contains":   {1, 1, stringContains},
+	"replace":    {2, 2, stringReplace},
+	"startsWith": {1, 1, stringStartsWith},
+	"endsWith":   {1, 1, stringEndsWith},
+	"indexOf":    {1, 1, stringIndexOf},
+	"repeat":     {1, 1, stringRepeat},
+	"format":     {0, VARIADIC, stringFormat},
+}
+
+// GetStringMethod returns the method of a string bound to it
+func (i *Interpreter) GetStringMethod(s string, name *token.Token) (interface{}, error) {
+	m, ok := stringMethods[name.Lexeme]
+	if !ok {
+		return nil, i.RuntimeError(*name, "Strings have no method "+name.Lexeme)
+	}
+
+	return NewVariadicFunction(name.Lexeme, m.minArity, m.maxArity, func(i *Interpreter, args []interface{}) (interface{}, error) {
+		return m.method(i, s, args)
+	}), nil
+}
+
+// stringArg checks that an argument of a string method is a string
+func stringArg(method string, arg interface{}) (string, error) {
+	s, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("%s expects a string argument", method)
+	}
+	return s, nil
+}
+
+func stringUpper(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	return strings.ToUpper(s), nil
+}
+
+func stringLower(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	return strings.ToLower(s), nil
+}
+
+func stringTrim(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	return strings.TrimSpace(s), nil
+}
+
+// stringSplit splits a string around a separator into a list,
+// an empty separator splits the string into code points
+func stringSplit(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	sep, err := stringArg("split", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(s, sep)
+	elements := make([]interface{}, len(parts))
+	for index, part := range parts {
+		elements[index] = part
+	}
+	return NewList(elements), nil
+}
+
+func stringContains(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	sub, err := stringArg("contains", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.Contains(s, sub), nil
+}
+
+func stringReplace(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	old, err := stringArg("replace", args[0])
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := stringArg("replace", args[1])
+	if err != nil {
+		return nil, err
+	}
+	return strings.ReplaceAll(s, old, replacement), nil
+}
+
+func stringStartsWith(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	prefix, err := stringArg("startsWith", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasPrefix(s, prefix), nil
+}
+
+func stringEndsWith(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	suffix, err := stringArg("endsWith", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasSuffix(s, suffix), nil
+}
+
+// stringIndexOf returns the code point index of the first
+// occurrence of a substring, or -1 if it is not present
+func stringIndexOf(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	sub, err := stringArg("indexOf", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	index := strings.Index(s, sub)
+	if index < 0 {
+		return int64(-1), nil
+	}
+	return int64(utf8.RuneCountInString(s[:index])), nil
+}
+
+func stringRepeat(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	count, err := toIndex(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, errors.New("repeat expects a non negative count")
+	}
+	if len(s) > 0 && count > MAX_STRING_BYTES/len(s) {
+		return nil, errors.New("repeat would create a string that is too large")
+	}
+	return strings.Repeat(s, count), nil
+}
+
+// MAX_STRING_BYTES bounds the size of the strings that repeat is
+// allowed to produce
+const MAX_STRING_BYTES int = 1 << 28
+
+// stringFormat replaces the placeholders in a string with its
+// arguments, {} takes the next argument and {n} takes the nth
+// argument. {{ and }} are written as literal braces
+func stringFormat(i *Interpreter, s string, args []interface{}) (interface{}, error) {
+	var builder strings.Builder
+	next := 0
+
+	for pos := 0; pos < len(s); pos++ {
+		c := s[pos]
+		if c == '}' {
+			if pos+1 < len(s) && s[pos+1] == '}' {
+				pos++
+			}
+			builder.WriteByte('}')
+			continue
+		}
+		if c != '{' {
+			builder.WriteByte(c)
+			continue
+		}
+		if pos+1 < len(s) && s[pos+1] == '{' {
+			builder.WriteByte('{')
+			pos++
+			continue
+		}
+
+		end := strings.IndexByte(s[pos:], '}')
+		if end < 0 {
+			return nil, errors.New("format has an unclosed '{'")
+		}
+		placeholder := s[pos+1 : pos+end]
+		pos += end
+
+		index := next
+		if placeholder == "" {
+			next++
+		} else {
+			n, err := strconv.Atoi(placeholder)
+			if err != nil {
+				return nil, fmt.Errorf("format has an invalid placeholder {%s}", placeholder)
+			}
+			index = n
+		}
+		if index < 0 || index >= len(args) {
+			return nil, fmt.Errorf("format has no argument for placeholder %d", index)
+		}
+		s, err := i.Stringify(args[index])
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(s)
+	}
+
+	return builder.String(), nil
+}
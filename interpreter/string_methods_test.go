@@ -0,0 +1,70 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       interface{}
+	}{
+		{`"größe".upper()`, "GRÖßE"},
+		{`"HeLLo".lower()`, "hello"},
+		{`"  a b	 ".trim()`, "a b"},
+		{`"{}".format("a,b,,c".split(","))`, `["a", "b", "", "c"]`},
+		{`"{}".format("añb".split(""))`, `["a", "ñ", "b"]`},
+		{`"hello".contains("ell")`, true},
+		{`"hello".contains("xyz")`, false},
+		{`"a-b-c".replace("-", "+")`, "a+b+c"},
+		{`"hello".startsWith("he")`, true},
+		{`"hello".endsWith("he")`, false},
+		{`"größe".indexOf("e")`, int64(4)},
+		{`"hello".indexOf("z")`, int64(-1)},
+		{`"ab".repeat(3)`, "ababab"},
+		{`"ab".repeat(0)`, ""},
+		{`"{} + {} = {}".format(1, 2.5, 3.5)`, "1 + 2.5 = 3.5"},
+		{`"{1} {0} {1}".format("a", "b")`, "b a b"},
+		{`"{{}} {}".format(nil)`, "{} nil"},
+		{`"x".upper().repeat(2).lower()`, "xx"},
+	}
+	for _, test := range tests {
+		if got := evaluate(t, test.expression); got != test.want {
+			t.Errorf("%s: got %#v, want %#v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestStringMethodsAreBound(t *testing.T) {
+	execution, err := run(t, `
+var upper = "abc".upper;
+var result = upper();
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := global(t, execution, "result"); got != "ABC" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestStringMethodErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{`["abc"][0].nope()`, "Strings have no method nope"},
+		{`"abc".contains([1][0])`, "contains expects a string argument"},
+		{`"abc".repeat(-1)`, "non negative count"},
+		{`"abc".repeat(1 << 40)`, "too large"},
+		{`"{".format()`, "unclosed '{'"},
+		{`"{x}".format(1)`, "invalid placeholder {x}"},
+		{`"{} {}".format(1)`, "no argument for placeholder 1"},
+	}
+	for _, test := range tests {
+		if err := evaluateError(t, test.expression); !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.expression, err, test.want)
+		}
+	}
+}
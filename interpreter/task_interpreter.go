@@ -0,0 +1,262 @@
+package interpreter
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/Atul-Ranjan12/environment"
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles tasks started with spawn and the channels
+// they communicate over. A task has join(), returning its
+// result or its error, and done(). A channel has send(v),
+// recv(), giving nil once it is closed, and close(), and a for
+// in loop receives until the channel is closed. select runs
+// the first case that is ready, or its _ case when none is
+
+// Task is a call running on its own goroutine and interpreter.
+// Tasks share the globals and the values passed to them, lists
+// and maps are not guarded and must not be changed by two
+// tasks at once
+type Task struct {
+	Name   string
+	done   chan struct{}
+	result interface{}
+	err    error
+}
+
+func (t *Task) String() string {
+	return "<task " + t.Name + ">"
+}
+
+// Join waits for the task to finish and returns its result,
+// an error in the task is returned to every caller of join.
+// The exit signal is returned when the program exits first
+func (t *Task) Join(l *EventLoop) (interface{}, error) {
+	select {
+	case <-t.done:
+		return t.result, t.err
+	case <-l.Stopped():
+		return nil, l.Exited()
+	}
+}
+
+// Channel is a channel values are sent over between tasks, a
+// channel without capacity hands each value directly from a
+// sender to a receiver
+type Channel struct {
+	Values chan interface{}
+	mutex  sync.Mutex
+	closed bool
+}
+
+func (c *Channel) String() string {
+	return "<channel>"
+}
+
+// NewChannel creates a channel holding up to capacity values
+// that are not received yet
+func NewChannel(capacity int) *Channel {
+	return &Channel{Values: make(chan interface{}, capacity)}
+}
+
+// Send waits until a value can be sent over the channel, the
+// exit signal is returned when the program exits first
+func (c *Channel) Send(l *EventLoop, value interface{}) (err error) {
+	// A channel closed while waiting panics
+	defer func() {
+		if recover() != nil {
+			err = errors.New("Send on a closed channel")
+		}
+	}()
+	select {
+	case c.Values <- value:
+		return nil
+	case <-l.Stopped():
+		return l.Exited()
+	}
+}
+
+// Recv waits for a value, ok is false once the channel is
+// closed and every value sent has been received. The exit
+// signal is returned when the program exits first
+func (c *Channel) Recv(l *EventLoop) (value interface{}, ok bool, err error) {
+	select {
+	case value, ok = <-c.Values:
+		return value, ok, nil
+	case <-l.Stopped():
+		return nil, false, l.Exited()
+	}
+}
+
+// Close closes the channel, waiting receivers get nil
+func (c *Channel) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return errors.New("Channel is already closed")
+	}
+	c.closed = true
+	close(c.Values)
+	return nil
+}
+
+// DefineTaskFunctions defines the natives for tasks
+func (i *Interpreter) DefineTaskFunctions() {
+	i.Define(i.Globals, NewVariadicFunction("channel", 0, 1, channel), "channel")
+}
+
+// channel creates a channel, the optional argument is the
+// number of values it holds before a send waits
+func channel(i *Interpreter, args []interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return NewChannel(0), nil
+	}
+	capacity, ok := args[0].(int64)
+	if !ok || capacity < 0 {
+		return nil, errors.New("channel expects a capacity that is a non negative integer but got " + i.stringify(args[0]))
+	}
+	return NewChannel(int(capacity)), nil
+}
+
+// VisitSpawnExpr starts a call as a task, the callee and the
+// arguments are evaluated before the task starts
+func (i *Interpreter) VisitSpawnExpr(expr *expressions.Spawn) (interface{}, error) {
+	function, arguments, err := i.PrepareCall(expr.Call)
+	if err != nil || function == nil {
+		return nil, err
+	}
+
+	task := &Task{Name: i.stringify(function), done: make(chan struct{})}
+	if f, ok := function.(*Function); ok {
+		task.Name = f.Declaration.Name.Lexeme
+	}
+
+	fork := i.Fork()
+	go func() {
+		defer close(task.done)
+		task.result, task.err = function.Call(fork, arguments)
+		// exit stops the program even when the task is never
+		// joined
+		if signal, ok := task.err.(*ExitSignal); ok {
+			fork.Loop.Exit(signal)
+		}
+	}()
+	return task, nil
+}
+
+// GetTaskMethod returns the method of a task bound to it
+func (i *Interpreter) GetTaskMethod(t *Task, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "join":
+		return NewNativeFunction("join", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return t.Join(i.Loop)
+		}), nil
+	case "done":
+		return NewNativeFunction("done", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			select {
+			case <-t.done:
+				return true, nil
+			default:
+				return false, nil
+			}
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Tasks have no method "+name.Lexeme)
+}
+
+// GetChannelMethod returns the method of a channel bound to it
+func (i *Interpreter) GetChannelMethod(c *Channel, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "send":
+		return NewNativeFunction("send", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return nil, c.Send(i.Loop, args[0])
+		}), nil
+	case "recv":
+		// recv gives nil once the channel is closed
+		return NewNativeFunction("recv", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			value, _, err := c.Recv(i.Loop)
+			return value, err
+		}), nil
+	case "close":
+		return NewNativeFunction("close", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return nil, c.Close()
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Channels have no method "+name.Lexeme)
+}
+
+// VisitSelectStmt waits until one of the cases can receive or
+// send and runs its body, the default case is run when no case
+// is ready. A case runs in a new environment holding the value
+// it received
+func (i *Interpreter) VisitSelectStmt(stmt *expressions.Select) (interface{}, error) {
+	cases := make([]reflect.SelectCase, 0, len(stmt.Cases)+1)
+	for _, selectCase := range stmt.Cases {
+		value, err := i.Evaluate(selectCase.Channel)
+		if err != nil {
+			return nil, err
+		}
+		channel, ok := value.(*Channel)
+		if !ok {
+			return nil, i.RuntimeError(selectCase.Operation, "Can only select on channels, got "+i.stringify(value))
+		}
+
+		if selectCase.Value == nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel.Values)})
+			continue
+		}
+		send, err := i.Evaluate(selectCase.Value)
+		if err != nil {
+			return nil, err
+		}
+		// A nil interface is not a valid reflect value
+		sendValue := reflect.Zero(reflect.TypeOf(channel.Values).Elem())
+		if send != nil {
+			sendValue = reflect.ValueOf(send)
+		}
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(channel.Values), Send: sendValue})
+	}
+	if stmt.Default != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+	// The last case stops waiting when the program exits
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(i.Loop.Stopped())})
+
+	chosen, received, err := i.selectCase(stmt, cases)
+	if err != nil {
+		return nil, err
+	}
+	if chosen == len(cases)-1 {
+		return nil, i.Loop.Exited()
+	}
+	if chosen == len(stmt.Cases) {
+		return i.Execute(stmt.Default)
+	}
+
+	selectCase := stmt.Cases[chosen]
+	env := environment.NewEnvironment(i.Environment)
+	if selectCase.Name != nil {
+		env.Define(selectCase.Name.Lexeme, received)
+	}
+	return nil, i.ExecuteBlock([]expressions.Stmt{selectCase.Body}, env)
+}
+
+// selectCase waits for one of the cases of a select, sending on
+// a closed channel is an error
+func (i *Interpreter) selectCase(stmt *expressions.Select, cases []reflect.SelectCase) (chosen int, received interface{}, err error) {
+	defer func() {
+		if recover() != nil {
+			err = i.RuntimeError(stmt.Keyword, "Send on a closed channel")
+		}
+	}()
+
+	chosen, value, ok := reflect.Select(cases)
+	if ok {
+		received = value.Interface()
+	}
+	return chosen, received, nil
+}
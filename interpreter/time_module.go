@@ -0,0 +1,432 @@
+package interpreter
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	// Time zones are embedded so they can be converted to on
+	// systems without a time zone database
+	_ "time/tzdata"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// This file handles the time module, points in time and the
+// durations between them. Layouts are the layouts of Go, the
+// way the reference time 2006-01-02 15:04:05 is written, and
+// the zone is UTC unless one is given. Durations are added to
+// and subtracted from times, times subtract to a duration and
+// durations scale by numbers. Times and durations compare with
+// == and the comparison operators, the same instant being
+// equal in any zone
+
+// Time is a point in time in a time zone
+type Time struct {
+	Time time.Time
+}
+
+func (t *Time) String() string {
+	return t.Time.Format(time.RFC3339Nano)
+}
+
+// Duration is the time between two points in time
+type Duration struct {
+	Duration time.Duration
+}
+
+func (d *Duration) String() string {
+	return d.Duration.String()
+}
+
+// DefineTimeModule defines the time module
+func (i *Interpreter) DefineTimeModule() {
+	module := NewModule("time")
+	module.Members["RFC3339"] = time.RFC3339
+	module.Members["DATE"] = "2006-01-02"
+	module.Members["TIME"] = "15:04:05"
+	module.Members["DATETIME"] = "2006-01-02 15:04:05"
+
+	module.Function("now", 0, 0, timeNow)
+	module.Function("date", 3, 7, timeDate)
+	module.Function("unix", 1, 1, timeUnix)
+	module.Function("parse", 2, 3, timeParse)
+	module.Function("since", 1, 1, timeSince)
+	module.Function("duration", 1, 1, timeDuration)
+	module.Function("milliseconds", 1, 1, durationFunction("milliseconds", time.Millisecond))
+	module.Function("seconds", 1, 1, durationFunction("seconds", time.Second))
+	module.Function("minutes", 1, 1, durationFunction("minutes", time.Minute))
+	module.Function("hours", 1, 1, durationFunction("hours", time.Hour))
+	module.Function("sleep", 1, 1, timeSleep)
+	i.Globals.Define(module.Name, module)
+}
+
+// timeArg checks that an argument of a time function is a time
+func timeArg(i *Interpreter, function string, arg interface{}) (*Time, error) {
+	t, ok := arg.(*Time)
+	if !ok {
+		return nil, errors.New(function + " expects a time but got " + i.stringify(arg))
+	}
+	return t, nil
+}
+
+// durationValueArg checks that an argument is a duration
+func durationValueArg(i *Interpreter, function string, arg interface{}) (*Duration, error) {
+	d, ok := arg.(*Duration)
+	if !ok {
+		return nil, errors.New(function + " expects a duration but got " + i.stringify(arg))
+	}
+	return d, nil
+}
+
+// intArg checks that an argument of a native is an integer
+// that fits in an int
+func intArg(i *Interpreter, function string, arg interface{}) (int, error) {
+	v, ok := arg.(int64)
+	if !ok || v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, errors.New(function + " expects an integer but got " + i.stringify(arg))
+	}
+	return int(v), nil
+}
+
+// zoneArg loads a time zone by its name, such as "UTC", "Local"
+// or "Europe/Paris"
+func zoneArg(i *Interpreter, function string, arg interface{}) (*time.Location, error) {
+	name, err := stringArg(function, arg)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New(function + ": unknown time zone " + name)
+	}
+	return location, nil
+}
+
+func timeNow(i *Interpreter, args []interface{}) (interface{}, error) {
+	return &Time{Time: time.Now()}, nil
+}
+
+// timeDate creates a time from its year, month, day and
+// optionally hour, minute, second and time zone, UTC by default
+func timeDate(i *Interpreter, args []interface{}) (interface{}, error) {
+	parts := []int{0, 1, 1, 0, 0, 0}
+	location := time.UTC
+	for index, arg := range args {
+		if index == 6 {
+			zone, err := zoneArg(i, "time.date", arg)
+			if err != nil {
+				return nil, err
+			}
+			location = zone
+			continue
+		}
+		part, err := intArg(i, "time.date", arg)
+		if err != nil {
+			return nil, err
+		}
+		parts[index] = part
+	}
+	return &Time{Time: time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, location)}, nil
+}
+
+// timeUnix creates a time from the seconds since the epoch
+func timeUnix(i *Interpreter, args []interface{}) (interface{}, error) {
+	if !i.IsNumber(args[0]) {
+		return nil, errors.New("time.unix expects a number of seconds but got " + i.stringify(args[0]))
+	}
+	seconds := toFloat(args[0])
+	if math.IsNaN(seconds) || seconds >= math.MaxInt64 || seconds < math.MinInt64 {
+		return nil, errors.New("time.unix expects a number of seconds in range but got " + i.stringify(args[0]))
+	}
+	whole, fraction := math.Modf(seconds)
+	return &Time{Time: time.Unix(int64(whole), int64(fraction*1e9)).UTC()}, nil
+}
+
+// timeParse reads a time written in a layout, a time without a
+// zone is in the zone given as the third argument, UTC by default
+func timeParse(i *Interpreter, args []interface{}) (interface{}, error) {
+	layout, err := stringArg("time.parse", args[0])
+	if err != nil {
+		return nil, err
+	}
+	text, err := stringArg("time.parse", args[1])
+	if err != nil {
+		return nil, err
+	}
+	location := time.UTC
+	if len(args) == 3 {
+		if location, err = zoneArg(i, "time.parse", args[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := time.ParseInLocation(layout, text, location)
+	if err != nil {
+		return nil, errors.New("time.parse: " + err.Error())
+	}
+	return &Time{Time: t}, nil
+}
+
+func timeSince(i *Interpreter, args []interface{}) (interface{}, error) {
+	t, err := timeArg(i, "time.since", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &Duration{Duration: time.Since(t.Time)}, nil
+}
+
+// timeDuration reads a duration such as "1h30m" or "250ms"
+func timeDuration(i *Interpreter, args []interface{}) (interface{}, error) {
+	text, err := stringArg("time.duration", args[0])
+	if err != nil {
+		return nil, err
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return nil, errors.New("time.duration: " + err.Error())
+	}
+	return &Duration{Duration: d}, nil
+}
+
+// durationFunction creates a function giving the duration of a
+// number of units
+func durationFunction(name string, unit time.Duration) func(i *Interpreter, args []interface{}) (interface{}, error) {
+	return func(i *Interpreter, args []interface{}) (interface{}, error) {
+		if !i.IsNumber(args[0]) {
+			return nil, errors.New("time." + name + " expects a number but got " + i.stringify(args[0]))
+		}
+		d, ok := durationOf(toFloat(args[0]) * float64(unit))
+		if !ok {
+			return nil, errors.New("time." + name + " expects a number giving a duration of at most 292 years but got " + i.stringify(args[0]))
+		}
+		return &Duration{Duration: d}, nil
+	}
+}
+
+// durationOf converts a number of nanoseconds to a duration, ok
+// is false when it is NaN or does not fit in a duration
+func durationOf(nanoseconds float64) (time.Duration, bool) {
+	// float64(math.MaxInt64) rounds up to 2**63
+	if math.IsNaN(nanoseconds) || nanoseconds >= math.MaxInt64 || nanoseconds < math.MinInt64 {
+		return 0, false
+	}
+	return time.Duration(nanoseconds), true
+}
+
+// timeSleep gives a promise fulfilled with nil after a duration
+// or a number of milliseconds, like sleep
+func timeSleep(i *Interpreter, args []interface{}) (interface{}, error) {
+	if d, ok := args[0].(*Duration); ok {
+		return sleep(i, []interface{}{float64(d.Duration) / float64(time.Millisecond)})
+	}
+	return sleep(i, args)
+}
+
+// GetTimeProperty returns a property of a time, the parts of the
+// time are properties and the rest are methods
+func (i *Interpreter) GetTimeProperty(t *Time, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "year":
+		return int64(t.Time.Year()), nil
+	case "month":
+		return int64(t.Time.Month()), nil
+	case "day":
+		return int64(t.Time.Day()), nil
+	case "hour":
+		return int64(t.Time.Hour()), nil
+	case "minute":
+		return int64(t.Time.Minute()), nil
+	case "second":
+		return int64(t.Time.Second()), nil
+	case "nanosecond":
+		return int64(t.Time.Nanosecond()), nil
+	case "weekday":
+		return t.Time.Weekday().String(), nil
+	case "yearDay":
+		return int64(t.Time.YearDay()), nil
+	case "unix":
+		return t.Time.Unix(), nil
+	case "unixMillis":
+		return t.Time.UnixMilli(), nil
+	case "zone":
+		zone, _ := t.Time.Zone()
+		return zone, nil
+	case "format":
+		return NewNativeFunction("format", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			layout, err := stringArg("format", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return t.Time.Format(layout), nil
+		}), nil
+	case "add":
+		return NewNativeFunction("add", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			d, err := durationValueArg(i, "add", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return &Time{Time: t.Time.Add(d.Duration)}, nil
+		}), nil
+	case "addDate":
+		// addDate adds years, months and days, a day that does
+		// not exist in the month carries over to the next one
+		return NewNativeFunction("addDate", 3, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			var parts [3]int
+			for index, arg := range args {
+				part, err := intArg(i, "addDate", arg)
+				if err != nil {
+					return nil, err
+				}
+				parts[index] = part
+			}
+			return &Time{Time: t.Time.AddDate(parts[0], parts[1], parts[2])}, nil
+		}), nil
+	case "sub":
+		return NewNativeFunction("sub", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			other, err := timeArg(i, "sub", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return &Duration{Duration: t.Time.Sub(other.Time)}, nil
+		}), nil
+	case "inZone":
+		return NewNativeFunction("inZone", 1, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			location, err := zoneArg(i, "inZone", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return &Time{Time: t.Time.In(location)}, nil
+		}), nil
+	case "utc":
+		return NewNativeFunction("utc", 0, func(i *Interpreter, args []interface{}) (interface{}, error) {
+			return &Time{Time: t.Time.UTC()}, nil
+		}), nil
+	}
+	return nil, i.RuntimeError(*name, "Times have no property "+name.Lexeme)
+}
+
+// GetDurationProperty returns a property of a duration in a unit
+func (i *Interpreter) GetDurationProperty(d *Duration, name *token.Token) (interface{}, error) {
+	switch name.Lexeme {
+	case "hours":
+		return d.Duration.Hours(), nil
+	case "minutes":
+		return d.Duration.Minutes(), nil
+	case "seconds":
+		return d.Duration.Seconds(), nil
+	case "milliseconds":
+		return d.Duration.Milliseconds(), nil
+	case "nanoseconds":
+		return d.Duration.Nanoseconds(), nil
+	}
+	return nil, i.RuntimeError(*name, "Durations have no property "+name.Lexeme)
+}
+
+// TimeBinary applies an operator to times and durations, ok is
+// false when neither operand is one. A duration is added to or
+// subtracted from a time, times subtract to a duration, and
+// durations are added, subtracted, scaled by a number or divided
+// by each other. Times and durations compare with each other
+func (i *Interpreter) TimeBinary(operator token.Token, left, right interface{}) (result interface{}, ok bool, err error) {
+	switch l := left.(type) {
+	case *Time:
+		switch r := right.(type) {
+		case *Duration:
+			switch operator.Type {
+			case token.PLUS:
+				return &Time{Time: l.Time.Add(r.Duration)}, true, nil
+			case token.MINUS:
+				return &Time{Time: l.Time.Add(-r.Duration)}, true, nil
+			}
+		case *Time:
+			if operator.Type == token.MINUS {
+				return &Duration{Duration: l.Time.Sub(r.Time)}, true, nil
+			}
+			cmp := 0
+			if l.Time.Before(r.Time) {
+				cmp = -1
+			} else if l.Time.After(r.Time) {
+				cmp = 1
+			}
+			if result, ok := compareOperator(operator.Type, cmp); ok {
+				return result, true, nil
+			}
+		}
+	case *Duration:
+		switch r := right.(type) {
+		case *Duration:
+			switch operator.Type {
+			case token.PLUS, token.MINUS:
+				other := r.Duration
+				if operator.Type == token.MINUS {
+					if other == math.MinInt64 {
+						return nil, true, i.RuntimeError(operator, "Duration is out of range")
+					}
+					other = -other
+				}
+				sum := l.Duration + other
+				if (other > 0 && sum < l.Duration) || (other < 0 && sum > l.Duration) {
+					return nil, true, i.RuntimeError(operator, "Duration is out of range")
+				}
+				return &Duration{Duration: sum}, true, nil
+			case token.SLASH:
+				if r.Duration == 0 {
+					return nil, true, i.RuntimeError(operator, "Division by a zero duration")
+				}
+				return float64(l.Duration) / float64(r.Duration), true, nil
+			}
+			cmp := 0
+			if l.Duration < r.Duration {
+				cmp = -1
+			} else if l.Duration > r.Duration {
+				cmp = 1
+			}
+			if result, ok := compareOperator(operator.Type, cmp); ok {
+				return result, true, nil
+			}
+		default:
+			if i.IsNumber(right) {
+				switch operator.Type {
+				case token.STAR:
+					return i.scaleDuration(operator, float64(l.Duration)*toFloat(right))
+				case token.SLASH:
+					if toFloat(right) == 0 {
+						return nil, true, i.RuntimeError(operator, "Division by zero")
+					}
+					return i.scaleDuration(operator, float64(l.Duration)/toFloat(right))
+				}
+			}
+		}
+	default:
+		// A number times a duration
+		d, isDuration := right.(*Duration)
+		if isDuration && i.IsNumber(left) && operator.Type == token.STAR {
+			return i.scaleDuration(operator, toFloat(left)*float64(d.Duration))
+		}
+		if _, isTime := right.(*Time); !isTime && !isDuration {
+			return nil, false, nil
+		}
+	}
+	return nil, true, i.RuntimeError(operator, "Operator '"+operator.Lexeme+"' can not be applied to "+i.stringify(left)+" and "+i.stringify(right))
+}
+
+// scaleDuration gives the duration of a scaled number of
+// nanoseconds for TimeBinary
+func (i *Interpreter) scaleDuration(operator token.Token, nanoseconds float64) (interface{}, bool, error) {
+	d, ok := durationOf(nanoseconds)
+	if !ok {
+		return nil, true, i.RuntimeError(operator, "Duration is out of range")
+	}
+	return &Duration{Duration: d}, true, nil
+}
+
+// compareOperator gives the value of a comparison operator for
+// the result of a comparison, ok is false for other operators
+func compareOperator(operator token.TokenType, cmp int) (result bool, ok bool) {
+	switch operator {
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		return compareResult(operator, cmp), true
+	}
+	return false, false
+}
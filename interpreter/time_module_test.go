@@ -0,0 +1,54 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Atul-Ranjan12/interpreter"
+)
+
+func TestDurations(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       time.Duration
+	}{
+		{"time.hours(1.5)", 90 * time.Minute},
+		{"time.seconds(2) * 3", 6 * time.Second},
+		{"2 * time.seconds(3)", 6 * time.Second},
+		{"time.seconds(3) / 2", 1500 * time.Millisecond},
+		{"time.hours(2562047) - time.hours(1)", 2562046 * time.Hour},
+	}
+	for _, test := range tests {
+		execution, err := run(t, "var result = "+test.expression+";")
+		if err != nil {
+			t.Fatalf("evaluating %s: %v", test.expression, err)
+		}
+		d, ok := global(t, execution, "result").(*interpreter.Duration)
+		if !ok || d.Duration != test.want {
+			t.Errorf("%s: got %v, want %v", test.expression, global(t, execution, "result"), test.want)
+		}
+	}
+}
+
+func TestDurationsOutOfRange(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"time.hours(1e30)", "292 years"},
+		{"time.seconds(-1e30)", "292 years"},
+		{"time.seconds(1e308 * 10 - 1e308 * 10)", "292 years"},
+		{"time.hours(2562047) * 2", "out of range"},
+		{"3e10 * time.hours(1)", "out of range"},
+		{"time.hours(1) / 1e-30", "out of range"},
+		{"time.hours(2562047) + time.hours(2562047)", "out of range"},
+		{"time.hours(-2562047) - time.hours(2562047)", "out of range"},
+		{"time.unix(1e300)", "in range"},
+	}
+	for _, test := range tests {
+		if err := evaluateError(t, test.expression); !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.expression, err, test.want)
+		}
+	}
+}
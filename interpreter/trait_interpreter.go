@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/Atul-Ranjan12/parser/expressions"
+)
+
+// Trait represents a trait in runtime, the resolver checks
+// that a struct implementing a trait defines its methods
+type Trait struct {
+	Name    string
+	Methods []string
+}
+
+func (t *Trait) String() string {
+	return "<trait " + t.Name + ">"
+}
+
+// VisitTraitStmt defines a trait
+func (i *Interpreter) VisitTraitStmt(stmt *expressions.Trait) (interface{}, error) {
+	methods := make([]string, len(stmt.Methods))
+	for index, method := range stmt.Methods {
+		methods[index] = method.Name.Lexeme
+	}
+
+	i.Environment.Define(stmt.Name.Lexeme, &Trait{Name: stmt.Name.Lexeme, Methods: methods})
+	return nil, nil
+}
+
+// Implements checks if a class declares that it implements
+// a trait
+func (c *Class) Implements(trait *Trait) bool {
+	for _, implemented := range c.Traits {
+		if implemented == trait {
+			return true
+		}
+	}
+	return false
+}
+
+// implements checks if an instance or a struct implements a trait
+func implements(i *Interpreter, args []interface{}) (interface{}, error) {
+	trait, ok := args[1].(*Trait)
+	if !ok {
+		return nil, fmt.Errorf("implements expects a trait but got %s", i.stringify(args[1]))
+	}
+
+	switch v := args[0].(type) {
+	case *Instance:
+		return v.ClassName.Implements(trait), nil
+	case *Class:
+		return v.Implements(trait), nil
+	}
+	return false, nil
+}
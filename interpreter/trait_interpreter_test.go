@@ -0,0 +1,60 @@
+package interpreter_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraits(t *testing.T) {
+	execution, err := run(t, `
+trait Drawable { draw(); }
+trait Named { name(); }
+struct Circle impl Drawable, Named {
+  construct(r) { this.r = r; }
+  draw() { return "circle " + "{}".format(this.r); }
+  name() { return "circle"; }
+}
+struct Square impl Drawable {
+  construct() {}
+  draw() { return "square"; }
+}
+struct Plain { construct() {} draw() { return "plain"; } }
+def render(shape: Drawable) { return shape.draw(); }
+var drawn = render(Circle(2)) + ", " + render(Square());
+var circle = implements(Circle(1), Drawable);
+var both = implements(Circle(1), Named);
+var square = implements(Square(), Named);
+var plain = implements(Plain(), Drawable);
+var class = implements(Circle, Named);
+var number = implements(1, Drawable);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want interface{}
+	}{
+		{"drawn", "circle 2, square"},
+		{"circle", true},
+		{"both", true},
+		{"square", false},
+		// Having the methods is not enough, the struct has to
+		// declare the trait
+		{"plain", false},
+		{"class", true},
+		{"number", false},
+	}
+	for _, test := range tests {
+		if got := global(t, execution, test.name); got != test.want {
+			t.Errorf("got %s %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestImplementsExpectsATrait(t *testing.T) {
+	_, err := run(t, `struct P { construct() {} } implements(P(), [P][0]);`)
+	if err == nil || !strings.Contains(err.Error(), "implements expects a trait but got") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package lang
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/Atul-Ranjan12/checker"
 	"github.com/Atul-Ranjan12/errorHandler"
 	"github.com/Atul-Ranjan12/interpreter"
 	"github.com/Atul-Ranjan12/lexer"
@@ -11,11 +14,13 @@ import (
 )
 
 type Lang struct {
-	HadError    bool
-	Lexer       *lexer.Lexer // The language has a lexer
-	Parser      *parser.Parser
-	Resolver    *resolver.Resolver
-	Interpreter *interpreter.Interpreter
+	HadError bool
+	// Errors holds the messages of the errors reported
+	Errors   []string
+	Lexer    *lexer.Lexer // The language has a lexer
+	Parser   *parser.Parser
+	Resolver *resolver.Resolver
+	Checker  *checker.Checker
 }
 
 // NewLang initializes an instance of lang
@@ -27,18 +32,52 @@ func NewLang(source string) *Lang {
 	tokens := lang.Lexer.ScanTokens()
 	// Initialize the parser
 	lang.Parser = parser.NewParser(tokens)
-	// Initialize the interpreter
-	lang.Interpreter = interpreter.NewInterpreter()
 	// Initialize the resolver
-	lang.Resolver = resolver.NewResolver(lang.Interpreter)
+	lang.Resolver = resolver.NewResolver()
+	// Initialize the type checker
+	lang.Checker = checker.NewChecker()
 	return lang
 }
 
+// Compile lexes, parses, resolves and type checks a source once.
+// The program can then be run any number of times, also from
+// many goroutines at once
+func Compile(source string) (*interpreter.Program, error) {
+	return NewLang(source).Compile()
+}
+
+// Compile parses, resolves and type checks the source of the
+// lang into a program
+func (l *Lang) Compile() (*interpreter.Program, error) {
+	if l.HasError() {
+		return nil, errors.New(strings.Join(l.Errors, "\n"))
+	}
+
+	statements, err := l.Parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Resolver.ResolveStatements(statements); err != nil {
+		return nil, err
+	}
+	if typeErrors := l.Checker.Check(statements); len(typeErrors) > 0 {
+		messages := make([]string, len(typeErrors))
+		for index, typeError := range typeErrors {
+			messages[index] = typeError.Error()
+		}
+		return nil, errors.New(strings.Join(messages, "\n"))
+	}
+
+	return interpreter.NewProgram(statements, l.Resolver.Locals, l.Resolver.Warnings), nil
+}
+
 func (l *Lang) Report(line int, where string, message string) {
 	fmt.Printf("[line %d] Error%s: %s\n", line, where, message)
 }
 
 func (l *Lang) Error(line int, message string) {
+	l.HadError = true
+	l.Errors = append(l.Errors, fmt.Sprintf("[line %d] Error: %s", line, message))
 	l.Report(line, "", message)
 }
 
@@ -48,6 +87,7 @@ func (l *Lang) HasError() bool {
 
 func (l *Lang) ResetError() {
 	l.HadError = false
+	l.Errors = nil
 }
 
 // Ensure Lang implements ErrorHandler
@@ -1,7 +1,11 @@
 package lexer
 
 import (
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Atul-Ranjan12/errorHandler"
 	"github.com/Atul-Ranjan12/token"
@@ -32,14 +36,16 @@ func NewLexer(source string, errorHandler errorHandler.ErrorHandler) *Lexer {
 	}
 }
 
-// Advance function gets the character at Current
-func (s *Lexer) Advance() byte {
+// Advance function gets the character at Current.
+// Start and Current are byte offsets into Source, but
+// the lexer always moves over whole UTF-8 code points
+func (s *Lexer) Advance() rune {
 	if s.IsAtEnd() {
 		return 0
 	}
 
-	char := s.Source[s.Current]
-	s.Current++
+	char, size := utf8.DecodeRuneInString(s.Source[s.Current:])
+	s.Current += size
 	return char
 }
 
@@ -60,48 +66,57 @@ func (s *Lexer) AddToken(tokenType token.TokenType, literal interface{}) {
 }
 
 // Match matches operators
-func (s *Lexer) Match(expected byte) bool {
+func (s *Lexer) Match(expected rune) bool {
 	if s.IsAtEnd() {
 		return false
 	}
-	if s.Source[s.Current] != expected {
+	char, size := utf8.DecodeRuneInString(s.Source[s.Current:])
+	if char != expected {
 		return false
 	}
-	s.Current++
+	s.Current += size
 	return true
 }
 
 // Peek returns the next character
-func (s *Lexer) Peek() byte {
+func (s *Lexer) Peek() rune {
 	if s.IsAtEnd() {
 		return 0
 	}
-	return s.Source[s.Current]
+	char, _ := utf8.DecodeRuneInString(s.Source[s.Current:])
+	return char
 }
 
-// IsDigit returns if a string is a digit
-func (s *Lexer) IsDigit(c byte) bool {
+// IsDigit returns if a character is a digit, number
+// literals only use the ASCII digits
+func (s *Lexer) IsDigit(c rune) bool {
 	return c >= '0' && c <= '9'
 }
 
-// IsAlpha returns if string is an alphabet
-func (s *Lexer) IsAlpha(c byte) bool {
-	return (c >= 'a' && c <= 'z') ||
-		(c >= 'A' && c <= 'Z') ||
-		c == '_'
+// IsAlpha returns if a character can start an identifier,
+// any Unicode letter is accepted
+func (s *Lexer) IsAlpha(c rune) bool {
+	return unicode.IsLetter(c) || c == '_'
 }
 
-// IsAlphaNumeric returns if string is an alphanumeric
-func (s *Lexer) IsAlphaNumeric(c byte) bool {
-	return s.IsAlpha(c) || s.IsDigit(c)
+// IsAlphaNumeric returns if a character can continue an
+// identifier, this includes Unicode digits and combining marks
+func (s *Lexer) IsAlphaNumeric(c rune) bool {
+	return s.IsAlpha(c) || s.IsDigit(c) ||
+		unicode.IsDigit(c) || unicode.IsMark(c)
 }
 
 // Get the next character in the source
-func (s *Lexer) PeekNext() byte {
-	if s.Current+1 >= len(s.Source) {
+func (s *Lexer) PeekNext() rune {
+	if s.IsAtEnd() {
+		return 0
+	}
+	_, size := utf8.DecodeRuneInString(s.Source[s.Current:])
+	if s.Current+size >= len(s.Source) {
 		return 0
 	}
-	return s.Source[s.Current+1]
+	char, _ := utf8.DecodeRuneInString(s.Source[s.Current+size:])
+	return char
 }
 
 // String function reads the entire string
@@ -130,29 +145,155 @@ func (s *Lexer) String() {
 	s.AddToken(token.STRING, value)
 }
 
-// Number function reads the number
-func (s *Lexer) Number() {
-	for s.IsDigit(s.Peek()) {
+// IsDigitOfBase returns if a character is a digit of
+// the given base
+func (s *Lexer) IsDigitOfBase(c rune, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return c >= '0' && c <= '7'
+	case 16:
+		return s.IsDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+	}
+	return s.IsDigit(c)
+}
+
+// Digits reads digits of a base, allowing underscores
+// between them
+func (s *Lexer) Digits(base int) {
+	for s.IsDigitOfBase(s.Peek(), base) || s.Peek() == '_' {
 		s.Advance()
 	}
+}
 
-	// Look for a fractional part.
-	if s.Peek() == '.' && s.IsDigit(s.PeekNext()) {
-		// Consume the "."
+// Number function reads the number. Integer literals produce
+// an int64, or a *big.Int if they do not fit, and literals with
+// a fraction or an exponent produce a float64
+func (s *Lexer) Number() {
+	base := 10
+	if s.Source[s.Start] == '0' {
+		switch s.Peek() {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+	}
+
+	isFloat := false
+	if base != 10 {
+		// Consume the base prefix
 		s.Advance()
+		s.Digits(base)
+	} else {
+		s.Digits(base)
 
-		for s.IsDigit(s.Peek()) {
+		// Look for a fractional part.
+		if s.Peek() == '.' && s.IsDigit(s.PeekNext()) {
+			// Consume the "."
 			s.Advance()
+			s.Digits(base)
+			isFloat = true
 		}
+
+		// Look for an exponent
+		if s.Peek() == 'e' || s.Peek() == 'E' {
+			next := s.PeekNext()
+			if s.IsDigit(next) || next == '+' || next == '-' {
+				s.Advance()
+				if !s.Match('+') {
+					s.Match('-')
+				}
+				s.Digits(base)
+				isFloat = true
+			}
+		}
+	}
+
+	text := s.Source[s.Start:s.Current]
+	digits := text
+	if base != 10 {
+		digits = text[2:]
+	}
+	if !s.ValidUnderscores(digits, base) {
+		s.Error("Invalid underscore in number literal " + text)
+		return
 	}
+	digits = strings.ReplaceAll(digits, "_", "")
 
-	value, err := strconv.ParseFloat(s.Source[s.Start:s.Current], 64)
-	if err != nil {
-		// TODO: Handle Error here
+	if isFloat {
+		value, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			s.Error("Invalid number literal " + text)
+			return
+		}
+		s.AddToken(token.NUMBER, value)
+		return
+	}
+
+	if value, err := strconv.ParseInt(digits, base, 64); err == nil {
+		s.AddToken(token.NUMBER, value)
+		return
+	}
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		s.Error("Invalid number literal " + text)
+		return
 	}
 	s.AddToken(token.NUMBER, value)
 }
 
+// ValidUnderscores checks that underscores in a number
+// only ever appear between two digits
+func (s *Lexer) ValidUnderscores(digits string, base int) bool {
+	if digits == "" {
+		return false
+	}
+	for index := 0; index < len(digits); index++ {
+		if digits[index] != '_' {
+			continue
+		}
+		if index == 0 || index == len(digits)-1 {
+			return false
+		}
+		before, after := rune(digits[index-1]), rune(digits[index+1])
+		if !s.IsDigitOfBase(before, base) || !s.IsDigitOfBase(after, base) {
+			return false
+		}
+	}
+	return true
+}
+
+// FollowsOperand checks if the token being scanned directly
+// follows a token that ends an operand, without whitespace in
+// between. "//" is integer division there and a comment
+// anywhere else, so 7//2 divides and 7 // 2 is a comment
+func (s *Lexer) FollowsOperand() bool {
+	if len(s.Tokens) == 0 || s.Start == 0 {
+		return false
+	}
+	switch s.Source[s.Start-1] {
+	case ' ', '\r', '\t', '\n':
+		return false
+	}
+	switch s.Tokens[len(s.Tokens)-1].Type {
+	case token.NUMBER, token.STRING, token.IDENTIFIER, token.RIGHT_PAREN, token.RIGHT_BRACKET,
+		token.TRUE, token.FALSE, token.NIL, token.THIS:
+		return true
+	}
+	return false
+}
+
+// Error reports an error at the current line
+func (s *Lexer) Error(message string) {
+	if s.ErrorHandler != nil {
+		s.ErrorHandler.Error(s.Line, message)
+	}
+}
+
 // Identifier checks if the token is an identifier
 func (s *Lexer) Identifier() {
 	for s.IsAlphaNumeric(s.Peek()) {
@@ -182,18 +323,50 @@ func (s *Lexer) ScanToken() {
 		s.AddToken(token.LEFT_BRACE, nil)
 	case '}':
 		s.AddToken(token.RIGHT_BRACE, nil)
+	case '[':
+		s.AddToken(token.LEFT_BRACKET, nil)
+	case ']':
+		s.AddToken(token.RIGHT_BRACKET, nil)
 	case ',':
 		s.AddToken(token.COMMA, nil)
 	case '.':
-		s.AddToken(token.DOT, nil)
+		if s.Peek() == '.' && s.PeekNext() == '.' {
+			s.Advance()
+			s.Advance()
+			s.AddToken(token.ELLIPSIS, nil)
+		} else {
+			s.AddToken(token.DOT, nil)
+		}
 	case '-':
 		s.AddToken(token.MINUS, nil)
 	case '+':
 		s.AddToken(token.PLUS, nil)
 	case ';':
 		s.AddToken(token.SEMICOLON, nil)
+	case ':':
+		s.AddToken(token.COLON, nil)
+	case '?':
+		if s.Match('?') {
+			s.AddToken(token.QUESTION_QUESTION, nil)
+		} else if s.Match('.') {
+			s.AddToken(token.QUESTION_DOT, nil)
+		} else {
+			s.AddToken(token.QUESTION, nil)
+		}
 	case '*':
-		s.AddToken(token.STAR, nil)
+		if s.Match('*') {
+			s.AddToken(token.STAR_STAR, nil)
+		} else {
+			s.AddToken(token.STAR, nil)
+		}
+	case '&':
+		s.AddToken(token.AMPERSAND, nil)
+	case '|':
+		s.AddToken(token.PIPE, nil)
+	case '^':
+		s.AddToken(token.CARET, nil)
+	case '~':
+		s.AddToken(token.TILDE, nil)
 	case '!':
 		if s.Match('=') {
 			s.AddToken(token.BANG_EQUAL, nil)
@@ -203,23 +376,32 @@ func (s *Lexer) ScanToken() {
 	case '=':
 		if s.Match('=') {
 			s.AddToken(token.EQUAL_EQUAL, nil)
+		} else if s.Match('>') {
+			s.AddToken(token.ARROW, nil)
 		} else {
 			s.AddToken(token.EQUAL, nil)
 		}
 	case '<':
 		if s.Match('=') {
 			s.AddToken(token.LESS_EQUAL, nil)
+		} else if s.Match('<') {
+			s.AddToken(token.LESS_LESS, nil)
 		} else {
 			s.AddToken(token.LESS, nil)
 		}
 	case '>':
 		if s.Match('=') {
 			s.AddToken(token.GREATER_EQUAL, nil)
+		} else if s.Match('>') {
+			s.AddToken(token.GREATER_GREATER, nil)
 		} else {
 			s.AddToken(token.GREATER, nil)
 		}
 	case '/':
-		if s.Match('/') {
+		if s.Peek() == '/' && s.FollowsOperand() {
+			s.Advance()
+			s.AddToken(token.SLASH_SLASH, nil)
+		} else if s.Match('/') {
 			// A comment goes until the end of the line.
 			for s.Peek() != '\n' && !s.IsAtEnd() {
 				// Just read the characters, do nothing
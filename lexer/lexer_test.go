@@ -0,0 +1,150 @@
+package lexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Atul-Ranjan12/token"
+)
+
+// errors collects the errors reported by the lexer
+type errors []string
+
+func (e *errors) Error(line int, message string) {
+	*e = append(*e, message)
+}
+
+func scan(t *testing.T, source string) []*token.Token {
+	t.Helper()
+	var reported errors
+	tokens := NewLexer(source, &reported).ScanTokens()
+	if len(reported) > 0 {
+		t.Fatalf("scanning %q reported %v", source, reported)
+	}
+	return tokens
+}
+
+func types(tokens []*token.Token) []token.TokenType {
+	result := make([]token.TokenType, len(tokens))
+	for index, t := range tokens {
+		result[index] = t.Type
+	}
+	return result
+}
+
+func TestOperatorsAndComments(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []token.TokenType
+	}{
+		// // right after an operand divides
+		{"7//2", []token.TokenType{token.NUMBER, token.SLASH_SLASH, token.NUMBER, token.EOF}},
+		{"f(x)//xs[0]", []token.TokenType{
+			token.IDENTIFIER, token.LEFT_PAREN, token.IDENTIFIER, token.RIGHT_PAREN, token.SLASH_SLASH,
+			token.IDENTIFIER, token.LEFT_BRACKET, token.NUMBER, token.RIGHT_BRACKET, token.EOF,
+		}},
+		{"~x", []token.TokenType{token.TILDE, token.IDENTIFIER, token.EOF}},
+		{"a / b", []token.TokenType{token.IDENTIFIER, token.SLASH, token.IDENTIFIER, token.EOF}},
+		{"2 ** 3", []token.TokenType{token.NUMBER, token.STAR_STAR, token.NUMBER, token.EOF}},
+		{"1 << 2 >> 3", []token.TokenType{token.NUMBER, token.LESS_LESS, token.NUMBER, token.GREATER_GREATER, token.NUMBER, token.EOF}},
+		// Anywhere else // starts a comment
+		{"x // half", []token.TokenType{token.IDENTIFIER, token.EOF}},
+		{"x = 1;// one", []token.TokenType{token.IDENTIFIER, token.EQUAL, token.NUMBER, token.SEMICOLON, token.EOF}},
+		{"// all of it 7//2", []token.TokenType{token.EOF}},
+		{"x\n//y", []token.TokenType{token.IDENTIFIER, token.EOF}},
+		{"while (i < 3) // count up\ni", []token.TokenType{
+			token.WHILE, token.LEFT_PAREN, token.IDENTIFIER, token.LESS, token.NUMBER, token.RIGHT_PAREN, token.IDENTIFIER, token.EOF,
+		}},
+	}
+	for _, test := range tests {
+		got := types(scan(t, test.source))
+		if len(got) != len(test.want) {
+			t.Errorf("%q: got %v, want %v", test.source, got, test.want)
+			continue
+		}
+		for index := range got {
+			if got[index] != test.want[index] {
+				t.Errorf("%q: got %v, want %v", test.source, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{"42", int64(42)},
+		{"1_000_000", int64(1000000)},
+		{"0xFF", int64(255)},
+		{"0b1010", int64(10)},
+		{"0o17", int64(15)},
+		{"2.5", 2.5},
+		{"1e3", 1000.0},
+		{"123456789012345678901234567890", huge},
+	}
+	for _, test := range tests {
+		tokens := scan(t, test.source)
+		if tokens[0].Type != token.NUMBER {
+			t.Errorf("%q: got a %v token", test.source, tokens[0].Type)
+			continue
+		}
+		switch want := test.want.(type) {
+		case *big.Int:
+			got, ok := tokens[0].Literal.(*big.Int)
+			if !ok || got.Cmp(want) != 0 {
+				t.Errorf("%q: got %v, want %v", test.source, tokens[0].Literal, want)
+			}
+		default:
+			if tokens[0].Literal != want {
+				t.Errorf("%q: got %#v, want %#v", test.source, tokens[0].Literal, want)
+			}
+		}
+	}
+}
+
+func TestInvalidNumberLiterals(t *testing.T) {
+	for _, source := range []string{"1__0", "0x", "0b", "1_"} {
+		var reported errors
+		NewLexer(source, &reported).ScanTokens()
+		if len(reported) == 0 {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
+
+func TestUnicode(t *testing.T) {
+	tokens := scan(t, "var größe = \"héllo wörld 👋\";\nπ_2 + x́")
+	want := []struct {
+		tokenType token.TokenType
+		lexeme    string
+		line      int
+	}{
+		{token.VAR, "var", 1},
+		{token.IDENTIFIER, "größe", 1},
+		{token.EQUAL, "=", 1},
+		{token.STRING, "\"héllo wörld 👋\"", 1},
+		{token.SEMICOLON, ";", 1},
+		{token.IDENTIFIER, "π_2", 2},
+		{token.PLUS, "+", 2},
+		// A combining mark continues the identifier
+		{token.IDENTIFIER, "x́", 2},
+		{token.EOF, "", 2},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %v tokens, want %v", types(tokens), len(want))
+	}
+	for index, w := range want {
+		got := tokens[index]
+		if got.Type != w.tokenType || got.Lexeme != w.lexeme || got.Line != w.line {
+			t.Errorf("token %d: got %v %q on line %d, want %v %q on line %d",
+				index, got.Type, got.Lexeme, got.Line, w.tokenType, w.lexeme, w.line)
+		}
+	}
+	if literal := tokens[3].Literal; literal != "héllo wörld 👋" {
+		t.Errorf("got the literal %q", literal)
+	}
+}
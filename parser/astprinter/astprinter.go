@@ -17,6 +17,7 @@ func NewAstPrinter() *ASTPrinter {
 // Specify explicitly to implement ExprVisitor and StmtVisitor
 var _ expressions.ExprVisitor = (*ASTPrinter)(nil)
 var _ expressions.StmtVisitor = (*ASTPrinter)(nil)
+var _ expressions.PatternVisitor = (*ASTPrinter)(nil)
 
 func (p *ASTPrinter) parenthesize(name string, exprs ...expressions.Expr) (string, error) {
 	var builder strings.Builder
@@ -87,15 +88,48 @@ func (p *ASTPrinter) VisitWhileStatementStmt(stmt *expressions.WhileStatement) (
 	return fmt.Sprintf("(while %s %s)", condition, body), nil
 }
 
+func (p *ASTPrinter) VisitForInStmt(stmt *expressions.ForIn) (interface{}, error) {
+	iterable, err := stmt.Iterable.Accept(p)
+	if err != nil {
+		return nil, err
+	}
+	body, err := stmt.Body.Accept(p)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("(for %s in %s %s)", stmt.Name.Lexeme, iterable, body), nil
+}
+
+func (p *ASTPrinter) VisitYieldStmt(stmt *expressions.Yield) (interface{}, error) {
+	return p.parenthesize("yield", stmt.Value)
+}
+
 func (p *ASTPrinter) VisitVarStmt(stmt *expressions.Var) (interface{}, error) {
+	keyword := "var"
+	if stmt.Constant {
+		keyword = "const"
+	}
+	name := stmt.Name.Lexeme + typeName(stmt.Type)
 	if stmt.Initializer == nil {
-		return fmt.Sprintf("(var %s)", stmt.Name.Lexeme), nil
+		return fmt.Sprintf("(%s %s)", keyword, name), nil
 	}
 	initializer, err := stmt.Initializer.Accept(p)
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf("(var %s %s)", stmt.Name.Lexeme, initializer), nil
+	return fmt.Sprintf("(%s %s %s)", keyword, name, initializer), nil
+}
+
+// typeName writes a type annotation, nothing is written when
+// there is no annotation
+func typeName(annotation *expressions.TypeAnnotation) string {
+	if annotation == nil {
+		return ""
+	}
+	if annotation.Nullable {
+		return ":" + annotation.Name.Lexeme + "?"
+	}
+	return ":" + annotation.Name.Lexeme
 }
 
 func (p *ASTPrinter) VisitIfStmt(stmt *expressions.If) (interface{}, error) {
@@ -175,6 +209,10 @@ func (p *ASTPrinter) VisitLogicalExpr(expr *expressions.Logical) (interface{}, e
 	return p.parenthesize(expr.Operator.Lexeme, expr.Left, expr.Right)
 }
 
+func (p *ASTPrinter) VisitConditionalExpr(expr *expressions.Conditional) (interface{}, error) {
+	return p.parenthesize("?:", expr.Condition, expr.ThenBranch, expr.ElseBranch)
+}
+
 func (p *ASTPrinter) VisitVariableExpr(expr *expressions.Variable) (interface{}, error) {
 	return expr.Name.Lexeme, nil
 }
@@ -187,7 +225,34 @@ func (p *ASTPrinter) VisitCallExpr(expr *expressions.Call) (interface{}, error)
 	args := make([]expressions.Expr, len(expr.Arguments)+1)
 	args[0] = expr.Callee
 	copy(args[1:], expr.Arguments)
-	return p.parenthesize("call", args...)
+	call, err := p.parenthesize("call", args...)
+	if err != nil || len(expr.Keywords) == 0 {
+		return call, err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(strings.TrimSuffix(call, ")"))
+	for index, keyword := range expr.Keywords {
+		value, err := expr.KeywordValues[index].Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(" " + keyword.Lexeme + ":" + value.(string))
+	}
+	builder.WriteString(")")
+	return builder.String(), nil
+}
+
+func (p *ASTPrinter) VisitSpreadExpr(expr *expressions.Spread) (interface{}, error) {
+	return p.parenthesize("...", expr.Expression)
+}
+
+func (p *ASTPrinter) VisitSpawnExpr(expr *expressions.Spawn) (interface{}, error) {
+	return p.parenthesize("spawn", expr.Call)
+}
+
+func (p *ASTPrinter) VisitAwaitExpr(expr *expressions.Await) (interface{}, error) {
+	return p.parenthesize("await", expr.Value)
 }
 
 func (p *ASTPrinter) VisitFunctionStmt(stmt *expressions.Function) (interface{}, error) {
@@ -200,10 +265,24 @@ func (p *ASTPrinter) VisitFunctionStmt(stmt *expressions.Function) (interface{},
 		if i > 0 {
 			builder.WriteString(" ")
 		}
+		if stmt.Variadic && i == len(stmt.Params)-1 {
+			builder.WriteString("...")
+		}
 		builder.WriteString(param.Lexeme)
+		builder.WriteString(typeName(stmt.ParamTypes[i]))
+		if stmt.Defaults[i] != nil {
+			result, err := stmt.Defaults[i].Accept(p)
+			if err != nil {
+				return nil, err
+			}
+			builder.WriteString("=")
+			builder.WriteString(result.(string))
+		}
 	}
 
-	builder.WriteString(") ")
+	builder.WriteString(")")
+	builder.WriteString(typeName(stmt.ReturnType))
+	builder.WriteString(" ")
 
 	for _, bodyStmt := range stmt.Body {
 		result, err := bodyStmt.Accept(p)
@@ -236,6 +315,48 @@ func (p *ASTPrinter) VisitClassStmt(stmt *expressions.Class) (interface{}, error
 	builder.WriteString("(class ")
 	builder.WriteString(stmt.Name.Lexeme)
 	builder.WriteString(" ")
+	for _, trait := range stmt.Traits {
+		builder.WriteString("(impl ")
+		builder.WriteString(trait.Name.Lexeme)
+		builder.WriteString(") ")
+	}
+	for i, field := range stmt.Fields {
+		builder.WriteString("(field ")
+		builder.WriteString(field.Lexeme)
+		builder.WriteString(typeName(stmt.FieldTypes[i]))
+		builder.WriteString(") ")
+	}
+	for _, method := range stmt.Methods {
+		result, err := method.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(result.(string))
+		builder.WriteString(" ")
+	}
+	for _, group := range []struct {
+		modifier  string
+		functions []*expressions.Function
+	}{{"static", stmt.StaticMethods}, {"get", stmt.Getters}, {"set", stmt.Setters}} {
+		for _, function := range group.functions {
+			result, err := function.Accept(p)
+			if err != nil {
+				return nil, err
+			}
+			builder.WriteString("(" + group.modifier + " ")
+			builder.WriteString(result.(string))
+			builder.WriteString(") ")
+		}
+	}
+	builder.WriteString(")")
+	return builder.String(), nil
+}
+
+func (p *ASTPrinter) VisitTraitStmt(stmt *expressions.Trait) (interface{}, error) {
+	var builder strings.Builder
+	builder.WriteString("(trait ")
+	builder.WriteString(stmt.Name.Lexeme)
+	builder.WriteString(" ")
 	for _, method := range stmt.Methods {
 		result, err := method.Accept(p)
 		if err != nil {
@@ -253,6 +374,9 @@ func (p *ASTPrinter) VisitGetExpr(expr *expressions.Get) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if expr.Optional {
+		return fmt.Sprintf("(get? %v %s)", object, expr.Name.Lexeme), nil
+	}
 	return fmt.Sprintf("(get %v %s)", object, expr.Name.Lexeme), nil
 }
 
@@ -268,6 +392,199 @@ func (p *ASTPrinter) VisitSetExpr(expr *expressions.Set) (interface{}, error) {
 	return fmt.Sprintf("(set %v %s %v)", object, expr.Name.Lexeme, value), nil
 }
 
+func (p *ASTPrinter) VisitListLiteralExpr(expr *expressions.ListLiteral) (interface{}, error) {
+	return p.parenthesize("list", expr.Elements...)
+}
+
+func (p *ASTPrinter) VisitMapLiteralExpr(expr *expressions.MapLiteral) (interface{}, error) {
+	entries := make([]expressions.Expr, 0, 2*len(expr.Keys))
+	for index, key := range expr.Keys {
+		entries = append(entries, key, expr.Values[index])
+	}
+	return p.parenthesize("map", entries...)
+}
+
+func (p *ASTPrinter) VisitDestructureStmt(stmt *expressions.Destructure) (interface{}, error) {
+	pattern, err := stmt.Pattern.Accept(p)
+	if err != nil {
+		return nil, err
+	}
+	initializer, err := stmt.Initializer.Accept(p)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.Constant {
+		return fmt.Sprintf("(const %s %s)", pattern, initializer), nil
+	}
+	return fmt.Sprintf("(var %s %s)", pattern, initializer), nil
+}
+
+func (p *ASTPrinter) VisitMultiAssignStmt(stmt *expressions.MultiAssign) (interface{}, error) {
+	targets, err := p.parenthesize("targets", stmt.Targets...)
+	if err != nil {
+		return nil, err
+	}
+	values, err := p.parenthesize("values", stmt.Values...)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("(= %s %s)", targets, values), nil
+}
+
+func (p *ASTPrinter) VisitMatchStmt(stmt *expressions.Match) (interface{}, error) {
+	value, err := stmt.Value.Accept(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	builder.WriteString("(match ")
+	builder.WriteString(value.(string))
+	for _, arm := range stmt.Arms {
+		builder.WriteString(" (arm")
+		for _, pattern := range arm.Patterns {
+			result, err := pattern.Accept(p)
+			if err != nil {
+				return nil, err
+			}
+			builder.WriteString(" ")
+			builder.WriteString(result.(string))
+		}
+		if arm.Guard != nil {
+			guard, err := p.parenthesize("if", arm.Guard)
+			if err != nil {
+				return nil, err
+			}
+			builder.WriteString(" ")
+			builder.WriteString(guard)
+		}
+		body, err := arm.Body.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(" ")
+		builder.WriteString(body.(string))
+		builder.WriteString(")")
+	}
+	builder.WriteString(")")
+	return builder.String(), nil
+}
+
+func (p *ASTPrinter) VisitSelectStmt(stmt *expressions.Select) (interface{}, error) {
+	var builder strings.Builder
+	builder.WriteString("(select")
+	for _, selectCase := range stmt.Cases {
+		operation, err := p.parenthesize(selectCase.Operation.Lexeme, selectCase.Channel)
+		if selectCase.Value != nil {
+			operation, err = p.parenthesize(selectCase.Operation.Lexeme, selectCase.Channel, selectCase.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		body, err := selectCase.Body.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+
+		builder.WriteString(" (case ")
+		if selectCase.Name != nil {
+			builder.WriteString(selectCase.Name.Lexeme + " = ")
+		}
+		builder.WriteString(operation + " " + body.(string) + ")")
+	}
+	if stmt.Default != nil {
+		body, err := stmt.Default.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(" (default " + body.(string) + ")")
+	}
+	builder.WriteString(")")
+	return builder.String(), nil
+}
+
+func (p *ASTPrinter) VisitConstantPattern(pattern *expressions.Constant) (interface{}, error) {
+	if pattern.Value == nil {
+		return "nil", nil
+	}
+	return fmt.Sprintf("%v", pattern.Value), nil
+}
+
+func (p *ASTPrinter) VisitWildcardPattern(pattern *expressions.Wildcard) (interface{}, error) {
+	return "_", nil
+}
+
+func (p *ASTPrinter) VisitBindingPattern(pattern *expressions.Binding) (interface{}, error) {
+	return pattern.Name.Lexeme, nil
+}
+
+func (p *ASTPrinter) VisitSequencePattern(pattern *expressions.Sequence) (interface{}, error) {
+	elements := make([]string, 0, len(pattern.Elements))
+	for _, element := range pattern.Elements {
+		result, err := element.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, result.(string))
+	}
+	return "[" + strings.Join(elements, " ") + "]", nil
+}
+
+func (p *ASTPrinter) VisitRecordPattern(pattern *expressions.Record) (interface{}, error) {
+	fields := make([]string, 0, len(pattern.Fields))
+	for index, field := range pattern.Fields {
+		result, err := pattern.Patterns[index].Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field.Lexeme+":"+result.(string))
+	}
+	name := ""
+	if pattern.Class != nil {
+		name = pattern.Class.Name.Lexeme
+	}
+	return name + "{" + strings.Join(fields, " ") + "}", nil
+}
+
+func (p *ASTPrinter) VisitIndexExpr(expr *expressions.Index) (interface{}, error) {
+	return p.parenthesize("index", expr.Object, expr.Index)
+}
+
+func (p *ASTPrinter) VisitSetIndexExpr(expr *expressions.SetIndex) (interface{}, error) {
+	return p.parenthesize("setindex", expr.Object, expr.Index, expr.Value)
+}
+
+func (p *ASTPrinter) VisitEnumStmt(stmt *expressions.Enum) (interface{}, error) {
+	variants := make([]string, len(stmt.Variants))
+	for index, variant := range stmt.Variants {
+		variants[index] = variant.Lexeme
+		if stmt.Fields[index] != nil {
+			fields := make([]string, len(stmt.Fields[index]))
+			for i, field := range stmt.Fields[index] {
+				fields[i] = field.Lexeme
+			}
+			variants[index] += "(" + strings.Join(fields, " ") + ")"
+		}
+	}
+	return "(enum " + stmt.Name.Lexeme + " " + strings.Join(variants, " ") + ")", nil
+}
+
+func (p *ASTPrinter) VisitVariantPattern(pattern *expressions.Variant) (interface{}, error) {
+	name := pattern.Enum.Name.Lexeme + "." + pattern.Name.Lexeme
+	if !pattern.HasPayload {
+		return name, nil
+	}
+	values := make([]string, len(pattern.Patterns))
+	for index, value := range pattern.Patterns {
+		result, err := value.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		values[index] = result.(string)
+	}
+	return name + "(" + strings.Join(values, " ") + ")", nil
+}
+
 func main() {
 	ExampleASTPrinter()
 }
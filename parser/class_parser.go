@@ -15,14 +15,95 @@ func (p *Parser) ClassDeclaration() (expressions.Stmt, error) {
 		return nil, err
 	}
 
+	// The traits the struct implements
+	var traits []*expressions.Variable
+	if p.Match(token.IMPL) {
+		for {
+			trait, err := p.Consume(token.IDENTIFIER, "Expect trait name after impl")
+			if err != nil {
+				return nil, err
+			}
+			traits = append(traits, &expressions.Variable{Name: *trait})
+
+			if !p.Match(token.COMMA) {
+				break
+			}
+		}
+	}
+
 	// Consume left brace
 	_, err = p.Consume(token.LEFT_BRACE, "Expect { after class identifier")
 	if err != nil {
 		return nil, err
 	}
 
+	var fields []token.Token
+	var fieldTypes []*expressions.TypeAnnotation
 	var functions []*expressions.Function
+	var staticMethods, getters, setters []*expressions.Function
 	for !p.Check(token.RIGHT_BRACE) && !p.IsAtEnd() {
+		if p.Match(token.ASYNC) {
+			method, err := p.AsyncMethod("method")
+			if err != nil {
+				return nil, err
+			}
+			functions = append(functions, method)
+			continue
+		}
+
+		// static, get and set are only modifiers before the
+		// name of a method so they can still be used as names
+		if p.Check(token.IDENTIFIER) && (p.Tokens[p.Current+1].Type == token.IDENTIFIER || p.Tokens[p.Current+1].Type == token.ASYNC) {
+			modifier := p.Advance()
+			async := p.Match(token.ASYNC)
+			if async && modifier.Lexeme != "static" {
+				return nil, p.Error(modifier, "Only a method or a static method can be async")
+			}
+			fn, err := p.Function("method")
+			if err != nil {
+				return nil, err
+			}
+			method := fn.(*expressions.Function)
+			method.Async = async
+
+			switch modifier.Lexeme {
+			case "static":
+				staticMethods = append(staticMethods, method)
+			case "get":
+				if len(method.Params) != 0 {
+					return nil, p.Error(&method.Name, "A getter can not have parameters")
+				}
+				getters = append(getters, method)
+			case "set":
+				if len(method.Params) != 1 || method.Variadic {
+					return nil, p.Error(&method.Name, "A setter must have exactly one parameter")
+				}
+				setters = append(setters, method)
+			default:
+				return nil, p.Error(modifier, "Expect static, get or set before method name")
+			}
+			continue
+		}
+
+		// A field declaration gives the type of a field
+		if p.Check(token.IDENTIFIER) && p.Tokens[p.Current+1].Type == token.COLON {
+			field := p.Advance()
+			p.Advance()
+
+			annotation, err := p.TypeAnnotation()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.Consume(token.SEMICOLON, "Expect ; after field declaration")
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, *field)
+			fieldTypes = append(fieldTypes, annotation)
+			continue
+		}
+
 		fn, err := p.Function("method")
 		if err != nil {
 			return nil, err
@@ -41,5 +122,101 @@ func (p *Parser) ClassDeclaration() (expressions.Stmt, error) {
 		return nil, err
 	}
 
-	return &expressions.Class{Name: *name, Methods: functions}, nil
+	return &expressions.Class{Name: *name, Traits: traits, Fields: fields, FieldTypes: fieldTypes, Methods: functions,
+		StaticMethods: staticMethods, Getters: getters, Setters: setters}, nil
+}
+
+// TraitDeclaration parses a trait, a list of method signatures
+// that a struct implementing the trait must define
+func (p *Parser) TraitDeclaration() (expressions.Stmt, error) {
+	name, err := p.Consume(token.IDENTIFIER, "Expect trait name")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.LEFT_BRACE, "Expect { after trait name")
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []*expressions.Function
+	for !p.Check(token.RIGHT_BRACE) && !p.IsAtEnd() {
+		method, err := p.Signature("method")
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.Consume(token.SEMICOLON, "Expect ; after trait method")
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	_, err = p.Consume(token.RIGHT_BRACE, "Expect } after trait body")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Trait{Name: *name, Methods: methods}, nil
+}
+
+// EnumDeclaration parses an enum, a list of variants where a
+// variant may name the values it carries
+func (p *Parser) EnumDeclaration() (expressions.Stmt, error) {
+	name, err := p.Consume(token.IDENTIFIER, "Expect enum name")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.LEFT_BRACE, "Expect { after enum name")
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []token.Token
+	var fields [][]token.Token
+	for !p.Check(token.RIGHT_BRACE) && !p.IsAtEnd() {
+		variant, err := p.Consume(token.IDENTIFIER, "Expect variant name")
+		if err != nil {
+			return nil, err
+		}
+
+		// A variant without parentheses carries no values
+		var variantFields []token.Token
+		if p.Match(token.LEFT_PAREN) {
+			variantFields = []token.Token{}
+			if !p.Check(token.RIGHT_PAREN) {
+				for {
+					field, err := p.Consume(token.IDENTIFIER, "Expect field name")
+					if err != nil {
+						return nil, err
+					}
+					variantFields = append(variantFields, *field)
+
+					if !p.Match(token.COMMA) {
+						break
+					}
+				}
+			}
+			_, err = p.Consume(token.RIGHT_PAREN, "Expect ) after variant fields")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		variants = append(variants, *variant)
+		fields = append(fields, variantFields)
+
+		if !p.Match(token.COMMA) {
+			break
+		}
+	}
+
+	_, err = p.Consume(token.RIGHT_BRACE, "Expect } after enum variants")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Enum{Name: *name, Variants: variants, Fields: fields}, nil
 }
@@ -9,15 +9,23 @@ type Expr interface {
 type ExprVisitor interface {
 	VisitAssignExpr(expr *Assign) (interface{}, error)
 	VisitLogicalExpr(expr *Logical) (interface{}, error)
+	VisitConditionalExpr(expr *Conditional) (interface{}, error)
 	VisitBinaryExpr(expr *Binary) (interface{}, error)
 	VisitCallExpr(expr *Call) (interface{}, error)
+	VisitSpreadExpr(expr *Spread) (interface{}, error)
+	VisitSpawnExpr(expr *Spawn) (interface{}, error)
+	VisitAwaitExpr(expr *Await) (interface{}, error)
 	VisitGetExpr(expr *Get) (interface{}, error)
 	VisitSetExpr(expr *Set) (interface{}, error)
+	VisitIndexExpr(expr *Index) (interface{}, error)
+	VisitSetIndexExpr(expr *SetIndex) (interface{}, error)
 	VisitThisExpr(expr *This) (interface{}, error)
 	VisitGroupingExpr(expr *Grouping) (interface{}, error)
 	VisitLiteralExpr(expr *Literal) (interface{}, error)
 	VisitUnaryExpr(expr *Unary) (interface{}, error)
 	VisitVariableExpr(expr *Variable) (interface{}, error)
+	VisitListLiteralExpr(expr *ListLiteral) (interface{}, error)
+	VisitMapLiteralExpr(expr *MapLiteral) (interface{}, error)
 	VisitBreakExprExpr(expr *BreakExpr) (interface{}, error)
 }
 
@@ -46,6 +54,19 @@ func (e *Logical) Accept(visitor ExprVisitor) (interface{}, error) {
 	return visitor.VisitLogicalExpr(e)
 }
 
+// These are functions for Conditional 
+type Conditional struct {
+	Condition Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+var _ Expr = (*Conditional)(nil)
+
+func (e *Conditional) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitConditionalExpr(e)
+}
+
 // These are functions for Binary 
 type Binary struct {
 	Left Expr
@@ -64,6 +85,8 @@ type Call struct {
 	Callee Expr
 	Paren token.Token
 	Arguments []Expr
+	Keywords []token.Token
+	KeywordValues []Expr
 }
 
 var _ Expr = (*Call)(nil)
@@ -72,10 +95,47 @@ func (e *Call) Accept(visitor ExprVisitor) (interface{}, error) {
 	return visitor.VisitCallExpr(e)
 }
 
+// These are functions for Spread 
+type Spread struct {
+	Ellipsis token.Token
+	Expression Expr
+}
+
+var _ Expr = (*Spread)(nil)
+
+func (e *Spread) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitSpreadExpr(e)
+}
+
+// These are functions for Spawn 
+type Spawn struct {
+	Keyword token.Token
+	Call *Call
+}
+
+var _ Expr = (*Spawn)(nil)
+
+func (e *Spawn) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitSpawnExpr(e)
+}
+
+// These are functions for Await 
+type Await struct {
+	Keyword token.Token
+	Value Expr
+}
+
+var _ Expr = (*Await)(nil)
+
+func (e *Await) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitAwaitExpr(e)
+}
+
 // These are functions for Get 
 type Get struct {
 	Object Expr
 	Name token.Token
+	Optional bool
 }
 
 var _ Expr = (*Get)(nil)
@@ -97,6 +157,33 @@ func (e *Set) Accept(visitor ExprVisitor) (interface{}, error) {
 	return visitor.VisitSetExpr(e)
 }
 
+// These are functions for Index 
+type Index struct {
+	Object Expr
+	Bracket token.Token
+	Index Expr
+}
+
+var _ Expr = (*Index)(nil)
+
+func (e *Index) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitIndexExpr(e)
+}
+
+// These are functions for SetIndex 
+type SetIndex struct {
+	Object Expr
+	Bracket token.Token
+	Index Expr
+	Value Expr
+}
+
+var _ Expr = (*SetIndex)(nil)
+
+func (e *SetIndex) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitSetIndexExpr(e)
+}
+
 // These are functions for This 
 type This struct {
 	Keyword token.Token
@@ -153,6 +240,31 @@ func (e *Variable) Accept(visitor ExprVisitor) (interface{}, error) {
 	return visitor.VisitVariableExpr(e)
 }
 
+// These are functions for ListLiteral 
+type ListLiteral struct {
+	Bracket token.Token
+	Elements []Expr
+}
+
+var _ Expr = (*ListLiteral)(nil)
+
+func (e *ListLiteral) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitListLiteralExpr(e)
+}
+
+// These are functions for MapLiteral 
+type MapLiteral struct {
+	Brace token.Token
+	Keys []Expr
+	Values []Expr
+}
+
+var _ Expr = (*MapLiteral)(nil)
+
+func (e *MapLiteral) Accept(visitor ExprVisitor) (interface{}, error) {
+	return visitor.VisitMapLiteralExpr(e)
+}
+
 // These are functions for BreakExpr 
 type BreakExpr struct {
 }
@@ -0,0 +1,13 @@
+package expressions
+
+import "github.com/Atul-Ranjan12/token"
+
+// MatchArm is a single arm of a match statement. The arm is
+// taken when any of its patterns matches the value and the
+// guard, if there is one, is truthy
+type MatchArm struct {
+	Arrow    token.Token
+	Patterns []Pattern
+	Guard    Expr
+	Body     Stmt
+}
@@ -0,0 +1,91 @@
+package expressions
+
+import "github.com/Atul-Ranjan12/token"
+
+type Pattern interface {
+	Accept(visitor PatternVisitor) (interface{}, error)
+}
+
+type PatternVisitor interface {
+	VisitConstantPattern(pattern *Constant) (interface{}, error)
+	VisitWildcardPattern(pattern *Wildcard) (interface{}, error)
+	VisitBindingPattern(pattern *Binding) (interface{}, error)
+	VisitSequencePattern(pattern *Sequence) (interface{}, error)
+	VisitRecordPattern(pattern *Record) (interface{}, error)
+	VisitVariantPattern(pattern *Variant) (interface{}, error)
+}
+
+// These are functions for Constant 
+type Constant struct {
+	Token token.Token
+	Value interface{}
+}
+
+var _ Pattern = (*Constant)(nil)
+
+func (e *Constant) Accept(visitor PatternVisitor) (interface{}, error) {
+	return visitor.VisitConstantPattern(e)
+}
+
+// These are functions for Wildcard 
+type Wildcard struct {
+	Token token.Token
+}
+
+var _ Pattern = (*Wildcard)(nil)
+
+func (e *Wildcard) Accept(visitor PatternVisitor) (interface{}, error) {
+	return visitor.VisitWildcardPattern(e)
+}
+
+// These are functions for Binding 
+type Binding struct {
+	Name token.Token
+}
+
+var _ Pattern = (*Binding)(nil)
+
+func (e *Binding) Accept(visitor PatternVisitor) (interface{}, error) {
+	return visitor.VisitBindingPattern(e)
+}
+
+// These are functions for Sequence 
+type Sequence struct {
+	Bracket token.Token
+	Elements []Pattern
+}
+
+var _ Pattern = (*Sequence)(nil)
+
+func (e *Sequence) Accept(visitor PatternVisitor) (interface{}, error) {
+	return visitor.VisitSequencePattern(e)
+}
+
+// These are functions for Record 
+type Record struct {
+	Brace token.Token
+	Class *Variable
+	Fields []token.Token
+	Patterns []Pattern
+}
+
+var _ Pattern = (*Record)(nil)
+
+func (e *Record) Accept(visitor PatternVisitor) (interface{}, error) {
+	return visitor.VisitRecordPattern(e)
+}
+
+// These are functions for Variant 
+type Variant struct {
+	Enum *Variable
+	Name token.Token
+	HasPayload bool
+	Patterns []Pattern
+}
+
+var _ Pattern = (*Variant)(nil)
+
+func (e *Variant) Accept(visitor PatternVisitor) (interface{}, error) {
+	return visitor.VisitVariantPattern(e)
+}
+
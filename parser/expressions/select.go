@@ -0,0 +1,15 @@
+package expressions
+
+import "github.com/Atul-Ranjan12/token"
+
+// SelectCase is a single case of a select statement, waiting to
+// receive from or send a value to a channel. A received value
+// is bound to Name when there is one
+type SelectCase struct {
+	Arrow     token.Token
+	Name      *token.Token
+	Channel   Expr
+	Operation token.Token
+	Value     Expr
+	Body      Stmt
+}
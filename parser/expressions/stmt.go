@@ -9,13 +9,21 @@ type Stmt interface {
 type StmtVisitor interface {
 	VisitBlockStmt(stmt *Block) (interface{}, error)
 	VisitClassStmt(stmt *Class) (interface{}, error)
+	VisitTraitStmt(stmt *Trait) (interface{}, error)
+	VisitEnumStmt(stmt *Enum) (interface{}, error)
 	VisitExprStatementStmt(stmt *ExprStatement) (interface{}, error)
 	VisitPrintStatementStmt(stmt *PrintStatement) (interface{}, error)
 	VisitReturnStmt(stmt *Return) (interface{}, error)
 	VisitWhileStatementStmt(stmt *WhileStatement) (interface{}, error)
+	VisitForInStmt(stmt *ForIn) (interface{}, error)
+	VisitYieldStmt(stmt *Yield) (interface{}, error)
 	VisitVarStmt(stmt *Var) (interface{}, error)
+	VisitDestructureStmt(stmt *Destructure) (interface{}, error)
+	VisitMultiAssignStmt(stmt *MultiAssign) (interface{}, error)
 	VisitIfStmt(stmt *If) (interface{}, error)
 	VisitFunctionStmt(stmt *Function) (interface{}, error)
+	VisitMatchStmt(stmt *Match) (interface{}, error)
+	VisitSelectStmt(stmt *Select) (interface{}, error)
 }
 
 // These are functions for Block 
@@ -32,7 +40,13 @@ func (e *Block) Accept(visitor StmtVisitor) (interface{}, error) {
 // These are functions for Class 
 type Class struct {
 	Name token.Token
+	Traits []*Variable
+	Fields []token.Token
+	FieldTypes []*TypeAnnotation
 	Methods []*Function
+	StaticMethods []*Function
+	Getters []*Function
+	Setters []*Function
 }
 
 var _ Stmt = (*Class)(nil)
@@ -41,6 +55,31 @@ func (e *Class) Accept(visitor StmtVisitor) (interface{}, error) {
 	return visitor.VisitClassStmt(e)
 }
 
+// These are functions for Trait 
+type Trait struct {
+	Name token.Token
+	Methods []*Function
+}
+
+var _ Stmt = (*Trait)(nil)
+
+func (e *Trait) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitTraitStmt(e)
+}
+
+// These are functions for Enum 
+type Enum struct {
+	Name token.Token
+	Variants []token.Token
+	Fields [][]token.Token
+}
+
+var _ Stmt = (*Enum)(nil)
+
+func (e *Enum) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitEnumStmt(e)
+}
+
 // These are functions for ExprStatement 
 type ExprStatement struct {
 	Expression Expr
@@ -87,10 +126,38 @@ func (e *WhileStatement) Accept(visitor StmtVisitor) (interface{}, error) {
 	return visitor.VisitWhileStatementStmt(e)
 }
 
+// These are functions for ForIn 
+type ForIn struct {
+	Keyword token.Token
+	Name token.Token
+	Iterable Expr
+	Body Stmt
+}
+
+var _ Stmt = (*ForIn)(nil)
+
+func (e *ForIn) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitForInStmt(e)
+}
+
+// These are functions for Yield 
+type Yield struct {
+	Keyword token.Token
+	Value Expr
+}
+
+var _ Stmt = (*Yield)(nil)
+
+func (e *Yield) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitYieldStmt(e)
+}
+
 // These are functions for Var 
 type Var struct {
 	Name token.Token
+	Type *TypeAnnotation
 	Initializer Expr
+	Constant bool
 }
 
 var _ Stmt = (*Var)(nil)
@@ -99,6 +166,33 @@ func (e *Var) Accept(visitor StmtVisitor) (interface{}, error) {
 	return visitor.VisitVarStmt(e)
 }
 
+// These are functions for Destructure 
+type Destructure struct {
+	Keyword token.Token
+	Pattern Pattern
+	Initializer Expr
+	Constant bool
+}
+
+var _ Stmt = (*Destructure)(nil)
+
+func (e *Destructure) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitDestructureStmt(e)
+}
+
+// These are functions for MultiAssign 
+type MultiAssign struct {
+	Targets []Expr
+	Equals token.Token
+	Values []Expr
+}
+
+var _ Stmt = (*MultiAssign)(nil)
+
+func (e *MultiAssign) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitMultiAssignStmt(e)
+}
+
 // These are functions for If 
 type If struct {
 	Condition Expr
@@ -116,7 +210,13 @@ func (e *If) Accept(visitor StmtVisitor) (interface{}, error) {
 type Function struct {
 	Name token.Token
 	Params []token.Token
+	ParamTypes []*TypeAnnotation
+	Defaults []Expr
+	Variadic bool
+	ReturnType *TypeAnnotation
 	Body []Stmt
+	Generator bool
+	Async bool
 }
 
 var _ Stmt = (*Function)(nil)
@@ -125,3 +225,29 @@ func (e *Function) Accept(visitor StmtVisitor) (interface{}, error) {
 	return visitor.VisitFunctionStmt(e)
 }
 
+// These are functions for Match 
+type Match struct {
+	Keyword token.Token
+	Value Expr
+	Arms []*MatchArm
+}
+
+var _ Stmt = (*Match)(nil)
+
+func (e *Match) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitMatchStmt(e)
+}
+
+// These are functions for Select 
+type Select struct {
+	Keyword token.Token
+	Cases []*SelectCase
+	Default Stmt
+}
+
+var _ Stmt = (*Select)(nil)
+
+func (e *Select) Accept(visitor StmtVisitor) (interface{}, error) {
+	return visitor.VisitSelectStmt(e)
+}
+
@@ -0,0 +1,12 @@
+package expressions
+
+import "github.com/Atul-Ranjan12/token"
+
+// TypeAnnotation is an optional static type written after a
+// variable, parameter, field or function, such as x: string?
+// Annotations are only used by the checker and never change
+// how a program runs
+type TypeAnnotation struct {
+	Name     token.Token
+	Nullable bool
+}
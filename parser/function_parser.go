@@ -23,11 +23,30 @@ func (p *Parser) Call() (expressions.Expr, error) {
 			}
 		} else if p.Match(token.DOT) {
 			// Return a get expression
-			name, err := p.Consume(token.IDENTIFIER, "Expect property name after .")
+			name, err := p.PropertyName("Expect property name after .")
 			if err != nil {
 				return nil, err
 			}
 			expr = &expressions.Get{Name: *name, Object: expr}
+		} else if p.Match(token.QUESTION_DOT) {
+			// Optional chaining, evaluates to nil when the object is nil
+			name, err := p.PropertyName("Expect property name after ?.")
+			if err != nil {
+				return nil, err
+			}
+			expr = &expressions.Get{Name: *name, Object: expr, Optional: true}
+		} else if p.Match(token.LEFT_BRACKET) {
+			// Indexing a list, map, string or instance
+			bracket := p.Prev()
+			index, err := p.Expression()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.Consume(token.RIGHT_BRACKET, "Expect ] after index")
+			if err != nil {
+				return nil, err
+			}
+			expr = &expressions.Index{Object: expr, Bracket: *bracket, Index: index}
 		} else {
 			break
 		}
@@ -36,21 +55,53 @@ func (p *Parser) Call() (expressions.Expr, error) {
 	return expr, nil
 }
 
-// FinishCall function handles parsing the arguments
+// PropertyName parses the name of a property after . or ?.,
+// which may be a keyword such as match
+func (p *Parser) PropertyName(message string) (*token.Token, error) {
+	if _, ok := token.Keywords[p.Peek().Lexeme]; ok && p.Peek().Type != token.IDENTIFIER {
+		name := *p.Advance()
+		name.Type = token.IDENTIFIER
+		return &name, nil
+	}
+	return p.Consume(token.IDENTIFIER, message)
+}
+
+// FinishCall function handles parsing the arguments. Positional
+// arguments, which may be spread with ..., come before keyword
+// arguments written as name: value
 func (p *Parser) FinishCall(callee expressions.Expr) (expressions.Expr, error) {
 	var arguments []expressions.Expr
+	var keywords []token.Token
+	var keywordValues []expressions.Expr
 
 	if !p.Check(token.RIGHT_PAREN) {
 		for {
-			if len(arguments) >= 255 {
+			if len(arguments)+len(keywords) >= 255 {
 				return nil, p.Error(p.Peek(), "Can't have more than 255 arguments.")
 			}
 
-			arg, err := p.Expression()
-			if err != nil {
-				return nil, err
+			if p.Check(token.IDENTIFIER) && p.Tokens[p.Current+1].Type == token.COLON {
+				// Keyword argument
+				name := p.Advance()
+				p.Advance()
+
+				value, err := p.Expression()
+				if err != nil {
+					return nil, err
+				}
+				keywords = append(keywords, *name)
+				keywordValues = append(keywordValues, value)
+			} else {
+				if len(keywords) > 0 {
+					return nil, p.Error(p.Peek(), "Positional argument can not follow a keyword argument.")
+				}
+
+				arg, err := p.Argument()
+				if err != nil {
+					return nil, err
+				}
+				arguments = append(arguments, arg)
 			}
-			arguments = append(arguments, arg)
 
 			if !p.Match(token.COMMA) {
 				break
@@ -63,11 +114,80 @@ func (p *Parser) FinishCall(callee expressions.Expr) (expressions.Expr, error) {
 		return nil, err
 	}
 
-	return &expressions.Call{Callee: callee, Paren: *paren, Arguments: arguments}, nil
+	return &expressions.Call{
+		Callee:        callee,
+		Paren:         *paren,
+		Arguments:     arguments,
+		Keywords:      keywords,
+		KeywordValues: keywordValues,
+	}, nil
+}
+
+// Argument parses an expression that may be spread with ...
+func (p *Parser) Argument() (expressions.Expr, error) {
+	if p.Match(token.ELLIPSIS) {
+		ellipsis := p.Prev()
+		expr, err := p.Expression()
+		if err != nil {
+			return nil, err
+		}
+		return &expressions.Spread{Ellipsis: *ellipsis, Expression: expr}, nil
+	}
+
+	return p.Expression()
 }
 
 // Function handles the parsing of function declarations
 func (p *Parser) Function(kind string) (expressions.Stmt, error) {
+	function, err := p.Signature(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Consume the right brace before parsing a block
+	_, err = p.Consume(token.LEFT_BRACE, "Expect '{' before function body")
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the body, a body containing a yield makes the
+	// function a generator
+	enclosingYielded := p.Yielded
+	p.Yielded = false
+	function.Body, err = p.Block()
+	if err != nil {
+		return nil, err
+	}
+	function.Generator = p.Yielded
+	p.Yielded = enclosingYielded
+
+	return function, nil
+}
+
+// AsyncFunction parses an async function declaration, the
+// async keyword has already been consumed
+func (p *Parser) AsyncFunction() (expressions.Stmt, error) {
+	_, err := p.Consume(token.FUN, "Expect def after async")
+	if err != nil {
+		return nil, err
+	}
+	return p.AsyncMethod("function")
+}
+
+// AsyncMethod parses a function or method marked async
+func (p *Parser) AsyncMethod(kind string) (*expressions.Function, error) {
+	fn, err := p.Function(kind)
+	if err != nil {
+		return nil, err
+	}
+	function := fn.(*expressions.Function)
+	function.Async = true
+	return function, nil
+}
+
+// Signature parses the name, parameters and return type of a
+// function, the body is left to the caller
+func (p *Parser) Signature(kind string) (*expressions.Function, error) {
 	// At this point we have already recognized the fun
 	// keyword, recognize an identifier after the keyword
 	name, err := p.Consume(token.IDENTIFIER, "Expect "+kind+" name")
@@ -81,21 +201,52 @@ func (p *Parser) Function(kind string) (expressions.Stmt, error) {
 		return nil, err
 	}
 
-	// Get the parameters
+	// Get the parameters, a parameter may have a default value
+	// and the last parameter may collect the remaining arguments
 	var parameters []token.Token
+	var types []*expressions.TypeAnnotation
+	var defaults []expressions.Expr
+	variadic := false
 	if !p.Check(token.RIGHT_PAREN) {
 		for {
 			if len(parameters) >= 255 {
 				return nil, p.Error(p.Peek(), "Can't have more than 255 arguments in a function")
 			}
 
+			if p.Match(token.ELLIPSIS) {
+				variadic = true
+			}
+
 			param, err := p.Consume(token.IDENTIFIER, "Expect parameter name")
 			if err != nil {
 				return nil, err
 			}
 
+			var annotation *expressions.TypeAnnotation
+			if p.Match(token.COLON) {
+				annotation, err = p.TypeAnnotation()
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			var defaultValue expressions.Expr
+			if !variadic && p.Match(token.EQUAL) {
+				defaultValue, err = p.Expression()
+				if err != nil {
+					return nil, err
+				}
+			} else if !variadic && len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+				return nil, p.Error(param, "Parameter without a default can not follow one with a default")
+			}
+
 			parameters = append(parameters, *param)
+			types = append(types, annotation)
+			defaults = append(defaults, defaultValue)
 
+			if variadic {
+				break
+			}
 			if !p.Match(token.COMMA) {
 				break
 			}
@@ -108,22 +259,22 @@ func (p *Parser) Function(kind string) (expressions.Stmt, error) {
 		return nil, err
 	}
 
-	// Consume the right brace before parsing a block
-	_, err = p.Consume(token.LEFT_BRACE, "Expect '{' before function body")
-	if err != nil {
-		return nil, err
-	}
-
-	// Get the body
-	body, err := p.Block()
-	if err != nil {
-		return nil, err
+	// The return type is optional
+	var returnType *expressions.TypeAnnotation
+	if p.Match(token.COLON) {
+		returnType, err = p.TypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &expressions.Function{
-		Name:   *name,
-		Params: parameters,
-		Body:   body,
+		Name:       *name,
+		Params:     parameters,
+		ParamTypes: types,
+		Defaults:   defaults,
+		Variadic:   variadic,
+		ReturnType: returnType,
 	}, nil
 }
 
@@ -154,3 +305,28 @@ func (p *Parser) ReturnStatement() (expressions.Stmt, error) {
 		Value:   value,
 	}, nil
 }
+
+// YieldStatement handles parsing a yield statement
+func (p *Parser) YieldStatement() (expressions.Stmt, error) {
+	keyword := p.Prev()
+	p.Yielded = true
+
+	var value expressions.Expr
+	var err error
+	if !p.Check(token.SEMICOLON) {
+		value, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.Consume(token.SEMICOLON, "Expect ; after yield statement")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Yield{
+		Keyword: *keyword,
+		Value:   value,
+	}, nil
+}
@@ -0,0 +1,274 @@
+package parser
+
+import (
+	"math/big"
+
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// MatchStatement parses a match statement, the match keyword
+// has already been consumed
+func (p *Parser) MatchStatement() (expressions.Stmt, error) {
+	keyword := p.Prev()
+
+	_, err := p.Consume(token.LEFT_PAREN, "Expect '(' after match")
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.RIGHT_PAREN, "Expect ')' after match value")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.LEFT_BRACE, "Expect '{' before match arms")
+	if err != nil {
+		return nil, err
+	}
+
+	var arms []*expressions.MatchArm
+	for !p.Check(token.RIGHT_BRACE) && !p.IsAtEnd() {
+		arm, err := p.MatchArm()
+		if err != nil {
+			return nil, err
+		}
+		arms = append(arms, arm)
+	}
+
+	_, err = p.Consume(token.RIGHT_BRACE, "Expect '}' after match arms")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Match{
+		Keyword: *keyword,
+		Value:   value,
+		Arms:    arms,
+	}, nil
+}
+
+// MatchArm parses a single arm of a match statement
+func (p *Parser) MatchArm() (*expressions.MatchArm, error) {
+	var patterns []expressions.Pattern
+	for {
+		pattern, err := p.Pattern()
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+
+		if !p.Match(token.COMMA) {
+			break
+		}
+	}
+
+	// Check for a guard
+	var guard expressions.Expr
+	var err error
+	if p.Match(token.IF) {
+		guard, err = p.Expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	arrow, err := p.Consume(token.ARROW, "Expect '=>' after match pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.MatchArm{
+		Arrow:    *arrow,
+		Patterns: patterns,
+		Guard:    guard,
+		Body:     body,
+	}, nil
+}
+
+// Pattern parses a single pattern
+func (p *Parser) Pattern() (expressions.Pattern, error) {
+	if p.Match(token.FALSE) {
+		return &expressions.Constant{Token: *p.Prev(), Value: false}, nil
+	}
+	if p.Match(token.TRUE) {
+		return &expressions.Constant{Token: *p.Prev(), Value: true}, nil
+	}
+	if p.Match(token.NIL) {
+		return &expressions.Constant{Token: *p.Prev(), Value: nil}, nil
+	}
+	if p.Match(token.NUMBER, token.STRING) {
+		return &expressions.Constant{Token: *p.Prev(), Value: p.Prev().Literal}, nil
+	}
+
+	// Negative number literals
+	if p.Match(token.MINUS) {
+		number, err := p.Consume(token.NUMBER, "Expect number after '-' in pattern")
+		if err != nil {
+			return nil, err
+		}
+		return &expressions.Constant{Token: *number, Value: negateLiteral(number.Literal)}, nil
+	}
+
+	if p.Match(token.LEFT_BRACKET) {
+		return p.SequencePattern()
+	}
+
+	// A struct pattern without a name matches fields of any
+	// struct instance or keys of a map
+	if p.Match(token.LEFT_BRACE) {
+		return p.RecordPattern(nil)
+	}
+
+	if p.Match(token.IDENTIFIER) {
+		name := p.Prev()
+		if name.Lexeme == "_" {
+			return &expressions.Wildcard{Token: *name}, nil
+		}
+		if p.Match(token.LEFT_BRACE) {
+			return p.RecordPattern(&expressions.Variable{Name: *name})
+		}
+		if p.Match(token.DOT) {
+			return p.VariantPattern(&expressions.Variable{Name: *name})
+		}
+		return &expressions.Binding{Name: *name}, nil
+	}
+
+	return nil, p.Error(p.Peek(), "Expect pattern.")
+}
+
+// SequencePattern parses a list pattern, the opening bracket
+// has already been consumed
+func (p *Parser) SequencePattern() (expressions.Pattern, error) {
+	bracket := p.Prev()
+
+	var elements []expressions.Pattern
+	if !p.Check(token.RIGHT_BRACKET) {
+		for {
+			element, err := p.Pattern()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+
+			if !p.Match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.Consume(token.RIGHT_BRACKET, "Expect ']' after list pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Sequence{Bracket: *bracket, Elements: elements}, nil
+}
+
+// RecordPattern parses the fields of a struct pattern, the
+// opening brace has already been consumed. A field without a
+// pattern binds the field to a variable of the same name. class
+// is nil for a pattern that is not restricted to one struct
+func (p *Parser) RecordPattern(class *expressions.Variable) (expressions.Pattern, error) {
+	brace := p.Prev()
+
+	var fields []token.Token
+	var patterns []expressions.Pattern
+	if !p.Check(token.RIGHT_BRACE) {
+		for {
+			field, err := p.Consume(token.IDENTIFIER, "Expect field name in struct pattern")
+			if err != nil {
+				return nil, err
+			}
+
+			var pattern expressions.Pattern = &expressions.Binding{Name: *field}
+			if p.Match(token.COLON) {
+				pattern, err = p.Pattern()
+				if err != nil {
+					return nil, err
+				}
+			}
+			fields = append(fields, *field)
+			patterns = append(patterns, pattern)
+
+			if !p.Match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.Consume(token.RIGHT_BRACE, "Expect '}' after struct pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Record{
+		Brace:    *brace,
+		Class:    class,
+		Fields:   fields,
+		Patterns: patterns,
+	}, nil
+}
+
+// VariantPattern parses a pattern matching a variant of an enum,
+// the dot after the enum has already been consumed. Without
+// parentheses the values of the variant are not matched
+func (p *Parser) VariantPattern(enum *expressions.Variable) (expressions.Pattern, error) {
+	name, err := p.Consume(token.IDENTIFIER, "Expect variant name after '.'")
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := &expressions.Variant{Enum: enum, Name: *name}
+	if !p.Match(token.LEFT_PAREN) {
+		return pattern, nil
+	}
+
+	pattern.HasPayload = true
+	if !p.Check(token.RIGHT_PAREN) {
+		for {
+			value, err := p.Pattern()
+			if err != nil {
+				return nil, err
+			}
+			pattern.Patterns = append(pattern.Patterns, value)
+
+			if !p.Match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err = p.Consume(token.RIGHT_PAREN, "Expect ')' after variant pattern")
+	if err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// negateLiteral negates the literal value of a number token
+func negateLiteral(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return -v
+	case *big.Int:
+		negated := new(big.Int).Neg(v)
+		if negated.IsInt64() {
+			return negated.Int64()
+		}
+		return negated
+	case float64:
+		return -v
+	}
+	return value
+}
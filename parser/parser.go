@@ -13,42 +13,75 @@ import (
 
 // Grammar for lang
 // program -> declaration* EOF ;
-// statement -> ifStatement | exprStatement | printStatement | forStatement | whileStatement | returnStatements | block;
+// statement -> ifStatement | exprStatement | printStatement | forStatement | forInStatement | whileStatement | returnStatements | yieldStatement | matchStatement | selectStatement | block;
 // returnStatement -> return expression ;
+// yieldStatement -> yield expression? ;
 // forStatement -> for ( varDeclaration | expression ; expression? ; expression? ) statement;
+// forInStatement -> for ( var IDENTIFIER in expression ) statement;
 // whileStatement -> while ( expression ) statement ;
 // ifStatement -> if ( expression ) statement (else statement)?
+// matchStatement -> match ( expression ) { matchArm* }
+// matchArm -> pattern ( , pattern )* ( if expression )? => statement
+// pattern -> literal | - NUMBER | _ | IDENTIFIER | [ ( pattern ( , pattern )* )? ]
+// 			  | IDENTIFIER? { ( field ( , field )* )? }
+// 			  | IDENTIFIER . IDENTIFIER ( ( ( pattern ( , pattern )* )? ) )?
+// field -> IDENTIFIER ( : pattern )?
+// selectStatement -> select { selectCase* ( _ => statement )? }
+// selectCase -> ( var IDENTIFIER = )? call . recv ( ) => statement | call . send ( expression ) => statement
 // block -> { declaration* }
-// declaration -> funcDeclaration | classDeclaration | varDeclaration | statement | breakStatement ;
-// classDeclaration -> class IDENTIFIER { function* }
-// funcDeclaration -> fun function ;
-// function -> IDENTIFIER ( parameters? ) block;
-// parameters -> IDENTIFIER ( , IDENTIFIER )*
+// declaration -> funcDeclaration | classDeclaration | traitDeclaration | enumDeclaration | varDeclaration | constDeclaration | statement | breakStatement ;
+// classDeclaration -> class IDENTIFIER ( impl IDENTIFIER ( , IDENTIFIER )* )? { member* }
+// member -> fieldDeclaration | async? function | static async? function | ( get | set ) function
+// traitDeclaration -> trait IDENTIFIER { ( signature ; )* }
+// enumDeclaration -> enum IDENTIFIER { ( variant ( , variant )* ,? )? }
+// variant -> IDENTIFIER ( ( ( IDENTIFIER ( , IDENTIFIER )* )? ) )?
+// fieldDeclaration -> IDENTIFIER : type ;
+// funcDeclaration -> async? fun function ;
+// function -> signature block;
+// signature -> IDENTIFIER ( parameters? ) ( : type )?
+// parameters -> parameter ( , parameter )* ( , ... IDENTIFIER ( : type )? )? | ... IDENTIFIER ( : type )?
+// parameter -> IDENTIFIER ( : type )? ( = expression )?
+// type -> ( IDENTIFIER | nil ) ( ? )?
 // breakStatement -> break ;
-// varDeclaration -> var + IDENTIFIER + ( = expression )? ;
-// exprStatement -> expression ;
+// varDeclaration -> var + IDENTIFIER ( : type )? + ( = expression )? ; | var pattern = expression ;
+// constDeclaration -> const + ( IDENTIFIER ( : type )? | pattern ) = expression ;
+// exprStatement -> expression ; | multiAssignment
+// multiAssignment -> call ( , call )+ = expression ( , expression )* ;
 // printStatement -> print ( expression ) ;
 
 // Grammar for expressions
 
 // expression -> assignment
-// assignment -> (call .)?IDENTIFIER = assignment | logic_or
+// assignment -> ( (call .)?IDENTIFIER | call [ expression ] ) = assignment | conditional
+// conditional -> nullish ( ? expression : conditional )?
+// nullish -> logic_or ( ?? logic_or )*
 // logic_or -> logic_and or logic_and
 // logic_and -> equality ( and equality )*
 // equality -> comparison ( ( != | == ) comparison)*
-// comparison -> term ( ( > | >= | < | <= ) term )*
-// term -> factor ( ( / | * ) factor)*
+// comparison -> bit_or ( ( > | >= | < | <= ) bit_or )*
+// bit_or -> bit_xor ( | bit_xor )*
+// bit_xor -> bit_and ( ^ bit_and )*
+// bit_and -> shift ( & shift )*
+// shift -> term ( ( << | >> ) term )*
+// term -> factor ( ( / | // | * ) factor)*
 // factor -> unary ( ( + | - ) unary)*
-// unary -> ( ! | - ) unary | call
-// call -> primary (( arguments? ) | . IDENTIFIER)* ;
-// arguments -> expression ( , expression )* ;
+// unary -> ( ! | - | ~ | await ) unary | spawn call | power
+// power -> call ( ** unary )?
+// call -> primary (( arguments? ) | . IDENTIFIER | ?. IDENTIFIER | [ expression ])* ;
+// arguments -> argument ( , argument )* ( , IDENTIFIER : expression )* | IDENTIFIER : expression ( , IDENTIFIER : expression )* ;
+// argument -> ...? expression
 // primary -> NUMBER | STRING | "true" | "false" | "nil"
-// 			  | "(" expression ")" | identifier
+// 			  | "(" expression ")" | identifier | "[" ( argument ( , argument )* )? "]"
+// 			  | "{" ( entry ( , entry )* )? "}"
+// entry -> ( IDENTIFIER | expression ) : expression
 
 // Parser represents the parser for lang
 type Parser struct {
 	Tokens  []*token.Token
 	Current int
+	// Yielded is set when the body of the function being
+	// parsed contains a yield, which makes it a generator
+	Yielded bool
 
 	// Handle errors
 	ErrorHandler errorHandler.ErrorHandler
@@ -67,7 +100,7 @@ func (p *Parser) Assignment() (expressions.Expr, error) {
 	// Handle the left hand side of the operation normally
 	// This should go down to returning that it is an identifier
 	// precisely expressions.Variable
-	expr, err := p.Or()
+	expr, err := p.Conditional()
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +123,7 @@ func (p *Parser) Assignment() (expressions.Expr, error) {
 			}, nil
 		}
 
-		if v, ok := expr.(*expressions.Get); ok {
+		if v, ok := expr.(*expressions.Get); ok && !v.Optional {
 			return &expressions.Set{
 				Name:   v.Name,
 				Object: v.Object,
@@ -98,6 +131,15 @@ func (p *Parser) Assignment() (expressions.Expr, error) {
 			}, nil
 		}
 
+		if v, ok := expr.(*expressions.Index); ok {
+			return &expressions.SetIndex{
+				Object:  v.Object,
+				Bracket: v.Bracket,
+				Index:   v.Index,
+				Value:   right,
+			}, nil
+		}
+
 		// Not a variable, neither a set expression
 		return nil, errors.New("Invalid assignment target")
 	}
@@ -105,6 +147,65 @@ func (p *Parser) Assignment() (expressions.Expr, error) {
 	return expr, nil
 }
 
+// Conditional handles the ternary conditional operator, the
+// else branch is parsed recursively so it is right associative
+func (p *Parser) Conditional() (expressions.Expr, error) {
+	condition, err := p.Nullish()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.Match(token.QUESTION) {
+		return condition, nil
+	}
+
+	thenBranch, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.COLON, "Expect ':' after then branch of conditional expression")
+	if err != nil {
+		return nil, err
+	}
+
+	elseBranch, err := p.Conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Conditional{
+		Condition:  condition,
+		ThenBranch: thenBranch,
+		ElseBranch: elseBranch,
+	}, nil
+}
+
+// Nullish handles the null coalescing operator, it is a
+// logical expression that only evaluates the right side
+// when the left side is nil
+func (p *Parser) Nullish() (expressions.Expr, error) {
+	expr, err := p.Or()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.Match(token.QUESTION_QUESTION) {
+		operator := p.Prev()
+		right, err := p.Or()
+		if err != nil {
+			return nil, err
+		}
+		expr = &expressions.Logical{
+			Left:     expr,
+			Operator: *operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
 // Expression is the root of the tree
 func (p *Parser) Expression() (expressions.Expr, error) {
 	return p.Assignment()
@@ -135,12 +236,104 @@ func (p *Parser) Equality() (expressions.Expr, error) {
 
 // Comparison checks if an expression is a comparison
 func (p *Parser) Comparison() (expressions.Expr, error) {
-	expr, err := p.Term()
+	expr, err := p.BitwiseOr()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.Match(token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL) {
+		operator := p.Prev()
+		right, err := p.BitwiseOr()
+		if err != nil {
+			return nil, err
+		}
+		expr = &expressions.Binary{
+			Left:     expr,
+			Operator: *operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// BitwiseOr checks if an expression is a bitwise or
+func (p *Parser) BitwiseOr() (expressions.Expr, error) {
+	expr, err := p.BitwiseXor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.Match(token.PIPE) {
+		operator := p.Prev()
+		right, err := p.BitwiseXor()
+		if err != nil {
+			return nil, err
+		}
+		expr = &expressions.Binary{
+			Left:     expr,
+			Operator: *operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// BitwiseXor checks if an expression is a bitwise xor
+func (p *Parser) BitwiseXor() (expressions.Expr, error) {
+	expr, err := p.BitwiseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.Match(token.CARET) {
+		operator := p.Prev()
+		right, err := p.BitwiseAnd()
+		if err != nil {
+			return nil, err
+		}
+		expr = &expressions.Binary{
+			Left:     expr,
+			Operator: *operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// BitwiseAnd checks if an expression is a bitwise and
+func (p *Parser) BitwiseAnd() (expressions.Expr, error) {
+	expr, err := p.Shift()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.Match(token.AMPERSAND) {
+		operator := p.Prev()
+		right, err := p.Shift()
+		if err != nil {
+			return nil, err
+		}
+		expr = &expressions.Binary{
+			Left:     expr,
+			Operator: *operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+// Shift checks if an expression is a bit shift
+func (p *Parser) Shift() (expressions.Expr, error) {
+	expr, err := p.Term()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.Match(token.LESS_LESS, token.GREATER_GREATER) {
 		operator := p.Prev()
 		right, err := p.Term()
 		if err != nil {
@@ -185,7 +378,7 @@ func (p *Parser) Factor() (expressions.Expr, error) {
 		return nil, err
 	}
 
-	for p.Match(token.SLASH, token.STAR) {
+	for p.Match(token.SLASH, token.SLASH_SLASH, token.STAR) {
 		operator := p.Prev()
 		right, err := p.Unary()
 		if err != nil {
@@ -203,7 +396,22 @@ func (p *Parser) Factor() (expressions.Expr, error) {
 
 // Unary checks if an expression is unary
 func (p *Parser) Unary() (expressions.Expr, error) {
-	if p.Match(token.BANG, token.MINUS) {
+	if p.Match(token.SPAWN) {
+		return p.Spawn()
+	}
+	if p.Match(token.AWAIT) {
+		keyword := p.Prev()
+		value, err := p.Unary()
+		if err != nil {
+			return nil, err
+		}
+		return &expressions.Await{
+			Keyword: *keyword,
+			Value:   value,
+		}, nil
+	}
+
+	if p.Match(token.BANG, token.MINUS, token.TILDE) {
 		operator := p.Prev()
 		right, err := p.Unary()
 		if err != nil {
@@ -215,8 +423,51 @@ func (p *Parser) Unary() (expressions.Expr, error) {
 		}, nil
 	}
 
+	return p.Power()
+}
+
+// Spawn parses a call started as a task, the spawn keyword has
+// already been consumed
+func (p *Parser) Spawn() (expressions.Expr, error) {
+	keyword := p.Prev()
+	expr, err := p.Call()
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*expressions.Call)
+	if !ok {
+		return nil, p.Error(keyword, "Expect a call after spawn")
+	}
+	return &expressions.Spawn{
+		Keyword: *keyword,
+		Call:    call,
+	}, nil
+}
+
+// Power checks if an expression is an exponentiation, it binds
+// tighter than a unary operator on its left and is right
+// associative, so -2 ** 2 is -(2 ** 2) and 2 ** 3 ** 2 is 2 ** 9
+func (p *Parser) Power() (expressions.Expr, error) {
 	// Could also be a function call now
-	return p.Call()
+	expr, err := p.Call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Match(token.STAR_STAR) {
+		operator := p.Prev()
+		right, err := p.Unary()
+		if err != nil {
+			return nil, err
+		}
+		expr = &expressions.Binary{
+			Left:     expr,
+			Operator: *operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
 }
 
 // Primary checks if an expression is primary
@@ -247,6 +498,14 @@ func (p *Parser) Primary() (expressions.Expr, error) {
 		return &expressions.BreakExpr{}, nil
 	}
 
+	if p.Match(token.LEFT_BRACKET) {
+		return p.ListLiteral()
+	}
+
+	if p.Match(token.LEFT_BRACE) {
+		return p.MapLiteral()
+	}
+
 	if p.Match(token.LEFT_PAREN) {
 		expr, err := p.Expression()
 		if err != nil {
@@ -262,29 +521,130 @@ func (p *Parser) Primary() (expressions.Expr, error) {
 	return nil, err
 }
 
+// ListLiteral parses the elements of a list, the opening
+// bracket has already been consumed
+func (p *Parser) ListLiteral() (expressions.Expr, error) {
+	bracket := p.Prev()
+
+	var elements []expressions.Expr
+	if !p.Check(token.RIGHT_BRACKET) {
+		for {
+			element, err := p.Argument()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+
+			if !p.Match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.Consume(token.RIGHT_BRACKET, "Expect ']' after list elements")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.ListLiteral{Bracket: *bracket, Elements: elements}, nil
+}
+
+// MapLiteral parses the entries of a map, the opening brace
+// has already been consumed. A bare identifier used as a key
+// is the string of its name
+func (p *Parser) MapLiteral() (expressions.Expr, error) {
+	brace := p.Prev()
+
+	var keys, values []expressions.Expr
+	if !p.Check(token.RIGHT_BRACE) {
+		for {
+			var key expressions.Expr
+			var err error
+			if p.Check(token.IDENTIFIER) && p.Tokens[p.Current+1].Type == token.COLON {
+				key = &expressions.Literal{Value: p.Advance().Lexeme}
+			} else {
+				key, err = p.Expression()
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			_, err = p.Consume(token.COLON, "Expect ':' after map key")
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := p.Expression()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values = append(values, value)
+
+			if !p.Match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.Consume(token.RIGHT_BRACE, "Expect '}' after map entries")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.MapLiteral{Brace: *brace, Keys: keys, Values: values}, nil
+}
+
 // Declaration handles each line of the program
 func (p *Parser) Declaration() (expressions.Stmt, error) {
 	if p.Match(token.CLASS) {
 		return p.ClassDeclaration()
 	}
+	if p.Match(token.TRAIT) {
+		return p.TraitDeclaration()
+	}
+	if p.Match(token.ENUM) {
+		return p.EnumDeclaration()
+	}
 	if p.Match(token.FUN) {
 		return p.Function("function")
 	}
+	if p.Match(token.ASYNC) {
+		return p.AsyncFunction()
+	}
 	if p.Match(token.VAR) {
 		return p.VariableDeclaration()
 	}
+	if p.Match(token.CONST) {
+		return p.ConstantDeclaration()
+	}
 
 	return p.Statement()
 }
 
 // VariableDeclaration checks a variable declaration
 func (p *Parser) VariableDeclaration() (expressions.Stmt, error) {
+	// A list or struct pattern declares a variable for each
+	// of the names bound by the pattern
+	if p.Check(token.LEFT_BRACKET) || p.Check(token.LEFT_BRACE) {
+		return p.DestructuringDeclaration()
+	}
+
 	// Check if it has a valid identifier
 	name, err := p.Consume(token.IDENTIFIER, "Expect variable name")
 	if err != nil {
 		return nil, err
 	}
 
+	// An optional type annotation follows the name
+	var annotation *expressions.TypeAnnotation
+	if p.Match(token.COLON) {
+		annotation, err = p.TypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if it has an equals after the identifier
 	var expression expressions.Expr = nil
 	if p.Match(token.EQUAL) {
@@ -300,10 +660,74 @@ func (p *Parser) VariableDeclaration() (expressions.Stmt, error) {
 	_, err = p.Consume(token.SEMICOLON, "Expect ; after variable declaration")
 	return &expressions.Var{
 		Name:        *name,
+		Type:        annotation,
 		Initializer: expression,
 	}, err
 }
 
+// TypeAnnotation parses the name of a type, a trailing ? allows
+// the value to also be nil
+func (p *Parser) TypeAnnotation() (*expressions.TypeAnnotation, error) {
+	if !p.Match(token.IDENTIFIER, token.NIL) {
+		return nil, p.Error(p.Peek(), "Expect type name")
+	}
+
+	annotation := &expressions.TypeAnnotation{Name: *p.Prev()}
+	if p.Match(token.QUESTION) {
+		annotation.Nullable = true
+	}
+	return annotation, nil
+}
+
+// ConstantDeclaration parses a declaration of a variable that
+// can not be assigned to, a constant always has an initializer
+func (p *Parser) ConstantDeclaration() (expressions.Stmt, error) {
+	stmt, err := p.VariableDeclaration()
+	if err != nil {
+		return nil, err
+	}
+
+	switch s := stmt.(type) {
+	case *expressions.Var:
+		s.Constant = true
+	case *expressions.Destructure:
+		s.Constant = true
+	}
+	return stmt, nil
+}
+
+// DestructuringDeclaration parses a declaration that binds
+// the variables of a pattern
+func (p *Parser) DestructuringDeclaration() (expressions.Stmt, error) {
+	keyword := p.Prev()
+
+	pattern, err := p.Pattern()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.EQUAL, "Expect = after destructuring pattern")
+	if err != nil {
+		return nil, err
+	}
+
+	initializer, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.Consume(token.SEMICOLON, "Expect ; after variable declaration")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Destructure{
+		Keyword:     *keyword,
+		Pattern:     pattern,
+		Initializer: initializer,
+	}, nil
+}
+
 // Consume checks if the current token is of the particular
 // tokenType, if it is it moves ahead, else it throws an
 // error
@@ -400,7 +824,7 @@ func (p *Parser) synchronize() {
 		}
 
 		switch p.Peek().Type {
-		case token.CLASS, token.FUN, token.VAR, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN:
+		case token.CLASS, token.TRAIT, token.ENUM, token.FUN, token.ASYNC, token.VAR, token.CONST, token.FOR, token.IF, token.MATCH, token.WHILE, token.PRINT, token.RETURN, token.YIELD, token.SELECT:
 			return
 		}
 
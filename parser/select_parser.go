@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"github.com/Atul-Ranjan12/parser/expressions"
+	"github.com/Atul-Ranjan12/token"
+)
+
+// SelectStatement parses a select statement, the select keyword
+// has already been consumed
+func (p *Parser) SelectStatement() (expressions.Stmt, error) {
+	keyword := p.Prev()
+
+	_, err := p.Consume(token.LEFT_BRACE, "Expect '{' after select")
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*expressions.SelectCase
+	var defaultBody expressions.Stmt
+	for !p.Check(token.RIGHT_BRACE) && !p.IsAtEnd() {
+		// _ => is taken when no other case is ready
+		if p.Peek().Type == token.IDENTIFIER && p.Peek().Lexeme == "_" {
+			wildcard := p.Advance()
+			if defaultBody != nil {
+				return nil, p.Error(wildcard, "Select can only have one default case")
+			}
+			_, err = p.Consume(token.ARROW, "Expect '=>' after select case")
+			if err != nil {
+				return nil, err
+			}
+			defaultBody, err = p.Statement()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		selectCase, err := p.SelectCase()
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, selectCase)
+	}
+
+	_, err = p.Consume(token.RIGHT_BRACE, "Expect '}' after select cases")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.Select{
+		Keyword: *keyword,
+		Cases:   cases,
+		Default: defaultBody,
+	}, nil
+}
+
+// SelectCase parses a case of a select statement, which is a
+// recv or send call on a channel with an optional variable
+// holding the received value
+func (p *Parser) SelectCase() (*expressions.SelectCase, error) {
+	var name *token.Token
+	var err error
+	if p.Match(token.VAR) {
+		name, err = p.Consume(token.IDENTIFIER, "Expect variable name")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.Consume(token.EQUAL, "Expect '=' after variable name")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	expr, err := p.Call()
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*expressions.Call)
+	if !ok {
+		return nil, p.Error(p.Prev(), "Expect a recv or send call on a channel")
+	}
+	get, ok := call.Callee.(*expressions.Get)
+	if !ok || get.Optional || len(call.Keywords) > 0 {
+		return nil, p.Error(&call.Paren, "Expect a recv or send call on a channel")
+	}
+
+	var value expressions.Expr
+	switch get.Name.Lexeme {
+	case "recv":
+		if len(call.Arguments) != 0 {
+			return nil, p.Error(&call.Paren, "recv takes no arguments")
+		}
+	case "send":
+		if name != nil {
+			return nil, p.Error(name, "Can only bind the value of a recv")
+		}
+		if len(call.Arguments) != 1 {
+			return nil, p.Error(&call.Paren, "send takes one argument")
+		}
+		if _, ok := call.Arguments[0].(*expressions.Spread); ok {
+			return nil, p.Error(&call.Paren, "Can not spread the value of a send")
+		}
+		value = call.Arguments[0]
+	default:
+		return nil, p.Error(&get.Name, "Expect a recv or send call on a channel")
+	}
+
+	arrow, err := p.Consume(token.ARROW, "Expect '=>' after select case")
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.SelectCase{
+		Arrow:     *arrow,
+		Name:      name,
+		Channel:   get.Object,
+		Operation: get.Name,
+		Value:     value,
+		Body:      body,
+	}, nil
+}
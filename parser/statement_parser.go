@@ -30,11 +30,26 @@ func (p *Parser) Statement() (expressions.Stmt, error) {
 		return p.ForStatement()
 	}
 
+	// Match the match statement
+	if p.Match(token.MATCH) {
+		return p.MatchStatement()
+	}
+
+	// Match the select statement
+	if p.Match(token.SELECT) {
+		return p.SelectStatement()
+	}
+
 	// Match for the return statement
 	if p.Match(token.RETURN) {
 		return p.ReturnStatement()
 	}
 
+	// Match for the yield statement
+	if p.Match(token.YIELD) {
+		return p.YieldStatement()
+	}
+
 	// Match left brace (block)
 	if p.Match(token.LEFT_BRACE) {
 		// Return the block
@@ -111,6 +126,11 @@ func (p *Parser) ExprStatement() (expressions.Stmt, error) {
 		return nil, err
 	}
 
+	// A comma after the first target starts a multiple assignment
+	if p.Check(token.COMMA) {
+		return p.MultiAssignment(value)
+	}
+
 	// Check for semicolon at the end
 	_, err = p.Consume(token.SEMICOLON, "Expect ; at the end of statement")
 	if err != nil {
@@ -122,6 +142,65 @@ func (p *Parser) ExprStatement() (expressions.Stmt, error) {
 	}, nil
 }
 
+// MultiAssignment parses an assignment to several targets at
+// once such as a, b = b, a; the first target is already parsed.
+// A single value on the right is unpacked as a list
+func (p *Parser) MultiAssignment(first expressions.Expr) (expressions.Stmt, error) {
+	targets := []expressions.Expr{first}
+	for p.Match(token.COMMA) {
+		target, err := p.Call()
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	for _, target := range targets {
+		switch t := target.(type) {
+		case *expressions.Variable, *expressions.Index:
+		case *expressions.Get:
+			if t.Optional {
+				return nil, p.Error(&t.Name, "Invalid assignment target")
+			}
+		default:
+			return nil, p.Error(p.Peek(), "Invalid assignment target")
+		}
+	}
+
+	equals, err := p.Consume(token.EQUAL, "Expect = after assignment targets")
+	if err != nil {
+		return nil, err
+	}
+
+	var values []expressions.Expr
+	for {
+		value, err := p.Expression()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		if !p.Match(token.COMMA) {
+			break
+		}
+	}
+
+	if len(values) != 1 && len(values) != len(targets) {
+		return nil, p.Error(equals, "Expect as many values as assignment targets")
+	}
+
+	_, err = p.Consume(token.SEMICOLON, "Expect ; at the end of statement")
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.MultiAssign{
+		Targets: targets,
+		Equals:  *equals,
+		Values:  values,
+	}, nil
+}
+
 // PrintStatement parses a print statement
 func (p *Parser) PrintStatement() (expressions.Stmt, error) {
 	// Parse the expression
@@ -227,8 +306,17 @@ func (p *Parser) And() (expressions.Expr, error) {
 // ForStatement parses a for statement by converting it
 // to a while statement
 func (p *Parser) ForStatement() (expressions.Stmt, error) {
+	keyword := p.Prev()
 	p.Consume(token.LEFT_PAREN, "Expect '(' after 'for'.")
 
+	// in is only a keyword after the loop variable, so
+	// for ( var IDENTIFIER in is an iterating loop
+	if p.Check(token.VAR) && p.Current+2 < len(p.Tokens) &&
+		p.Tokens[p.Current+1].Type == token.IDENTIFIER &&
+		p.Tokens[p.Current+2].Type == token.IDENTIFIER && p.Tokens[p.Current+2].Lexeme == "in" {
+		return p.ForInStatement(keyword)
+	}
+
 	var initializer expressions.Stmt
 	var err error
 	if p.Match(token.SEMICOLON) {
@@ -291,3 +379,33 @@ func (p *Parser) ForStatement() (expressions.Stmt, error) {
 
 	return body, nil
 }
+
+// ForInStatement parses a loop over the values of a list, the
+// keys of a map, the characters of a string or the values of
+// a generator
+func (p *Parser) ForInStatement(keyword *token.Token) (expressions.Stmt, error) {
+	p.Advance()
+	name := p.Advance()
+	p.Advance()
+
+	iterable, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.Consume(token.RIGHT_PAREN, "Expect ')' after for clauses.")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.Statement()
+	if err != nil {
+		return nil, err
+	}
+
+	return &expressions.ForIn{
+		Keyword:  *keyword,
+		Name:     *name,
+		Iterable: iterable,
+		Body:     body,
+	}, nil
+}
@@ -16,6 +16,7 @@ const (
 	FunctionTypeNone FunctionType = iota
 	FunctionTypeFunction
 	FunctionTypeMethod
+	FunctionTypeStatic
 )
 
 type ClassType int
@@ -27,22 +28,52 @@ const (
 )
 
 type Resolver struct {
-	Interpreter     *interpreter.Interpreter
-	Scopes          []map[string]bool
-	CurrentFunction FunctionType
-	CurrentClass    ClassType
-	FunctionDepth   int
+	// Locals holds the scope depth of every local variable
+	// used, the interpreter looks variables up with it
+	Locals map[expressions.Expr]int
+	Scopes []map[string]bool
+	// Constants holds the constants declared in each scope
+	// and GlobalConstants the ones declared at the top level
+	Constants       []map[string]bool
+	GlobalConstants map[string]bool
+	// TopLevelConstants holds every constant of the top level,
+	// collected before the program is resolved so a function
+	// declared before a constant can not assign to it
+	TopLevelConstants map[string]bool
+	// DeclaringConstant is set while the bindings of a
+	// constant destructuring declaration are declared
+	DeclaringConstant bool
+	CurrentFunction   FunctionType
+	CurrentClass      ClassType
+	// CurrentGenerator is set while resolving the body of a
+	// function containing yield
+	CurrentGenerator bool
+	// CurrentAsync is set while resolving the body of an
+	// async function
+	CurrentAsync bool
+	// Traits holds the declared traits so a struct can be
+	// checked against the traits it implements
+	Traits        map[string]*expressions.Trait
+	FunctionDepth int
+	// Warnings are reported for code that is valid
+	// but most likely a mistake
+	Warnings []string
 }
 
 var _ expressions.ExprVisitor = (*Resolver)(nil)
 var _ expressions.StmtVisitor = (*Resolver)(nil)
+var _ expressions.PatternVisitor = (*Resolver)(nil)
 
-func NewResolver(interpreter *interpreter.Interpreter) *Resolver {
+func NewResolver() *Resolver {
 	return &Resolver{
-		Interpreter:     interpreter,
-		Scopes:          []map[string]bool{},
-		CurrentFunction: FunctionTypeNone,
-		CurrentClass:    ClassTypeNone,
+		Locals:            make(map[expressions.Expr]int),
+		Scopes:            []map[string]bool{},
+		Constants:         []map[string]bool{},
+		GlobalConstants:   make(map[string]bool),
+		TopLevelConstants: make(map[string]bool),
+		Traits:            make(map[string]*expressions.Trait),
+		CurrentFunction:   FunctionTypeNone,
+		CurrentClass:      ClassTypeNone,
 	}
 }
 
@@ -50,6 +81,11 @@ func (r *Resolver) Error(token token.Token, message string) error {
 	return fmt.Errorf("Resolution Error at '%v': %s", token.Lexeme, message)
 }
 
+// Warn records a warning at the given token
+func (r *Resolver) Warn(token token.Token, message string) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf("[line %d] Warning at '%v': %s", token.Line, token.Lexeme, message))
+}
+
 func (r *Resolver) ResolveStatement(stmt expressions.Stmt) error {
 	_, err := stmt.Accept(r)
 	return err
@@ -60,7 +96,15 @@ func (r *Resolver) ResolveExpression(expr expressions.Expr) error {
 	return err
 }
 
+func (r *Resolver) ResolvePattern(pattern expressions.Pattern) error {
+	_, err := pattern.Accept(r)
+	return err
+}
+
 func (r *Resolver) ResolveStatements(statements []expressions.Stmt) error {
+	if len(r.Scopes) == 0 {
+		r.CollectConstants(statements)
+	}
 	for _, statement := range statements {
 		if err := r.ResolveStatement(statement); err != nil {
 			return err
@@ -71,17 +115,88 @@ func (r *Resolver) ResolveStatements(statements []expressions.Stmt) error {
 
 func (r *Resolver) BeginScope() {
 	r.Scopes = append(r.Scopes, make(map[string]bool))
+	r.Constants = append(r.Constants, make(map[string]bool))
 }
 
 func (r *Resolver) EndScope() {
 	if len(r.Scopes) > 0 {
 		r.Scopes = r.Scopes[:len(r.Scopes)-1]
+		r.Constants = r.Constants[:len(r.Constants)-1]
+	}
+}
+
+// CollectConstants finds the constants declared at the top level
+func (r *Resolver) CollectConstants(statements []expressions.Stmt) {
+	for _, statement := range statements {
+		switch stmt := statement.(type) {
+		case *expressions.Var:
+			if stmt.Constant {
+				r.TopLevelConstants[stmt.Name.Lexeme] = true
+			}
+		case *expressions.Destructure:
+			if stmt.Constant {
+				for _, name := range bindings(stmt.Pattern) {
+					r.TopLevelConstants[name.Lexeme] = true
+				}
+			}
+		}
+	}
+}
+
+// bindings gives the names a pattern binds
+func bindings(pattern expressions.Pattern) []token.Token {
+	var names []token.Token
+	var elements []expressions.Pattern
+	switch p := pattern.(type) {
+	case *expressions.Binding:
+		return []token.Token{p.Name}
+	case *expressions.Sequence:
+		elements = p.Elements
+	case *expressions.Record:
+		elements = p.Patterns
+	case *expressions.Variant:
+		elements = p.Patterns
+	}
+	for _, element := range elements {
+		names = append(names, bindings(element)...)
 	}
+	return names
+}
+
+// DeclareConstant marks a declared name as a constant
+func (r *Resolver) DeclareConstant(name token.Token) {
+	if len(r.Scopes) == 0 {
+		r.GlobalConstants[name.Lexeme] = true
+		return
+	}
+	r.Constants[len(r.Constants)-1][name.Lexeme] = true
+}
+
+// CheckAssignable reports an error when a name resolves
+// to a constant
+func (r *Resolver) CheckAssignable(name token.Token) error {
+	for i := len(r.Scopes) - 1; i >= 0; i-- {
+		if _, ok := r.Scopes[i][name.Lexeme]; ok {
+			if r.Constants[i][name.Lexeme] {
+				return r.Error(name, "Can not assign to a constant.")
+			}
+			return nil
+		}
+	}
+
+	if r.GlobalConstants[name.Lexeme] || r.TopLevelConstants[name.Lexeme] {
+		return r.Error(name, "Can not assign to a constant.")
+	}
+	return nil
 }
 
 func (r *Resolver) Declare(name token.Token) error {
 	// log.Println("This is r.Scopes right now: ", r.Scopes)
 	if len(r.Scopes) == 0 {
+		// Globals can be redeclared, except for constants
+		if r.GlobalConstants[name.Lexeme] {
+			return r.Error(name, "Can not redeclare a constant.")
+		}
 		return nil
 	}
 	scope := r.Scopes[len(r.Scopes)-1]
@@ -105,7 +220,7 @@ func (r *Resolver) ResolveLocal(expr expressions.Expr, name token.Token) {
 	for i := len(r.Scopes) - 1; i >= 0; i-- {
 		if _, ok := r.Scopes[i][name.Lexeme]; ok {
 			// Found it in scope
-			r.Interpreter.Resolve(expr, len(r.Scopes)-1-i)
+			r.Locals[expr] = len(r.Scopes) - 1 - i
 			// depth := len(r.Scopes) - 1 - i
 			// log.Printf("Found %s at depth %d", name.Lexeme, depth)
 			// if r.FunctionDepth > 0 && depth >= r.FunctionDepth {
@@ -123,17 +238,33 @@ func (r *Resolver) ResolveLocal(expr expressions.Expr, name token.Token) {
 
 // ResolveFunction resolves different types of functions
 func (r *Resolver) ResolveFunction(function *expressions.Function, funcType FunctionType) (interface{}, error) {
-	enclosingFunction := r.CurrentFunction
+	if function.Async && function.Generator {
+		return nil, r.Error(function.Name, "An async function can not yield.")
+	}
+
+	enclosingFunction, enclosingGenerator, enclosingAsync := r.CurrentFunction, r.CurrentGenerator, r.CurrentAsync
 	r.CurrentFunction = funcType
+	r.CurrentGenerator = function.Generator
+	r.CurrentAsync = function.Async
 
 	r.FunctionDepth++
 	defer func() {
 		r.FunctionDepth--
 		r.CurrentFunction = enclosingFunction
+		r.CurrentGenerator = enclosingGenerator
+		r.CurrentAsync = enclosingAsync
 	}()
 
 	r.BeginScope()
-	for _, param := range function.Params {
+	for index, param := range function.Params {
+		// A default is resolved before its parameter is
+		// declared so it can only use the parameters before it
+		if function.Defaults[index] != nil {
+			if err := r.ResolveExpression(function.Defaults[index]); err != nil {
+				r.EndScope()
+				return nil, err
+			}
+		}
 		if err := r.Declare(param); err != nil {
 			return nil, err
 		}
@@ -161,6 +292,9 @@ func (r *Resolver) VisitVarStmt(stmt *expressions.Var) (interface{}, error) {
 	if err := r.Declare(stmt.Name); err != nil {
 		return nil, err
 	}
+	if stmt.Constant {
+		r.DeclareConstant(stmt.Name)
+	}
 	if stmt.Initializer != nil {
 		if err := r.ResolveExpression(stmt.Initializer); err != nil {
 			return nil, err
@@ -170,6 +304,45 @@ func (r *Resolver) VisitVarStmt(stmt *expressions.Var) (interface{}, error) {
 	return nil, nil
 }
 
+// VisitDestructureStmt declares every variable bound by the
+// pattern of a destructuring declaration
+func (r *Resolver) VisitDestructureStmt(stmt *expressions.Destructure) (interface{}, error) {
+	if err := r.ResolveExpression(stmt.Initializer); err != nil {
+		return nil, err
+	}
+
+	r.DeclaringConstant = stmt.Constant
+	defer func() { r.DeclaringConstant = false }()
+	return nil, r.ResolvePattern(stmt.Pattern)
+}
+
+func (r *Resolver) VisitMultiAssignStmt(stmt *expressions.MultiAssign) (interface{}, error) {
+	for _, value := range stmt.Values {
+		if err := r.ResolveExpression(value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, target := range stmt.Targets {
+		switch t := target.(type) {
+		case *expressions.Variable:
+			if err := r.CheckAssignable(t.Name); err != nil {
+				return nil, err
+			}
+			r.ResolveLocal(t, t.Name)
+		case *expressions.Get:
+			if err := r.ResolveExpression(t.Object); err != nil {
+				return nil, err
+			}
+		case *expressions.Index:
+			if err := r.ResolveExpression(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
+
 func (r *Resolver) VisitVariableExpr(expr *expressions.Variable) (interface{}, error) {
 	// log.Println("Reaching here for: ", expr.Name.Lexeme)
 	if len(r.Scopes) > 0 {
@@ -185,6 +358,9 @@ func (r *Resolver) VisitAssignExpr(expr *expressions.Assign) (interface{}, error
 	if err := r.ResolveExpression(expr.Value); err != nil {
 		return nil, err
 	}
+	if err := r.CheckAssignable(expr.Name); err != nil {
+		return nil, err
+	}
 	r.ResolveLocal(expr, expr.Name)
 	return nil, nil
 }
@@ -225,6 +401,19 @@ func (r *Resolver) VisitReturnStmt(stmt *expressions.Return) (interface{}, error
 	if r.CurrentFunction == FunctionTypeNone {
 		return nil, errors.New("Can not return from top-level code")
 	}
+	if stmt.Value != nil {
+		if r.CurrentGenerator {
+			return nil, r.Error(stmt.Keyword, "Can not return a value from a generator.")
+		}
+		return nil, r.ResolveExpression(stmt.Value)
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitYieldStmt(stmt *expressions.Yield) (interface{}, error) {
+	if r.CurrentFunction == FunctionTypeNone {
+		return nil, r.Error(stmt.Keyword, "Can not yield from top-level code.")
+	}
 	if stmt.Value != nil {
 		return nil, r.ResolveExpression(stmt.Value)
 	}
@@ -238,6 +427,59 @@ func (r *Resolver) VisitWhileStatementStmt(stmt *expressions.WhileStatement) (in
 	return nil, r.ResolveStatement(stmt.Body)
 }
 
+// VisitSelectStmt resolves the channels and the values sent
+// before the cases, each case has a scope for the value it
+// receives
+func (r *Resolver) VisitSelectStmt(stmt *expressions.Select) (interface{}, error) {
+	for _, selectCase := range stmt.Cases {
+		if err := r.ResolveExpression(selectCase.Channel); err != nil {
+			return nil, err
+		}
+		if selectCase.Value != nil {
+			if err := r.ResolveExpression(selectCase.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, selectCase := range stmt.Cases {
+		r.BeginScope()
+		if selectCase.Name != nil {
+			if err := r.Declare(*selectCase.Name); err != nil {
+				r.EndScope()
+				return nil, err
+			}
+			r.Define(*selectCase.Name)
+		}
+		err := r.ResolveStatement(selectCase.Body)
+		r.EndScope()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.Default != nil {
+		return nil, r.ResolveStatement(stmt.Default)
+	}
+	return nil, nil
+}
+
+// VisitForInStmt resolves the body of a loop in a scope
+// holding the loop variable
+func (r *Resolver) VisitForInStmt(stmt *expressions.ForIn) (interface{}, error) {
+	if err := r.ResolveExpression(stmt.Iterable); err != nil {
+		return nil, err
+	}
+
+	r.BeginScope()
+	defer r.EndScope()
+	if err := r.Declare(stmt.Name); err != nil {
+		return nil, err
+	}
+	r.Define(stmt.Name)
+	return nil, r.ResolveStatement(stmt.Body)
+}
+
 func (r *Resolver) VisitBinaryExpr(expr *expressions.Binary) (interface{}, error) {
 	if err := r.ResolveExpression(expr.Left); err != nil {
 		return nil, err
@@ -256,9 +498,32 @@ func (r *Resolver) VisitCallExpr(expr *expressions.Call) (interface{}, error) {
 		}
 	}
 
+	for _, value := range expr.KeywordValues {
+		if err := r.ResolveExpression(value); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
+func (r *Resolver) VisitSpreadExpr(expr *expressions.Spread) (interface{}, error) {
+	return nil, r.ResolveExpression(expr.Expression)
+}
+
+func (r *Resolver) VisitSpawnExpr(expr *expressions.Spawn) (interface{}, error) {
+	return nil, r.ResolveExpression(expr.Call)
+}
+
+// VisitAwaitExpr checks that await is used in an async function
+// or at the top level, where it runs the event loop
+func (r *Resolver) VisitAwaitExpr(expr *expressions.Await) (interface{}, error) {
+	if r.CurrentFunction != FunctionTypeNone && !r.CurrentAsync {
+		return nil, r.Error(expr.Keyword, "Can only await in an async function or at the top level.")
+	}
+	return nil, r.ResolveExpression(expr.Value)
+}
+
 func (r *Resolver) VisitGroupingExpr(expr *expressions.Grouping) (interface{}, error) {
 	return nil, r.ResolveExpression(expr.Expression)
 }
@@ -274,6 +539,16 @@ func (r *Resolver) VisitLogicalExpr(expr *expressions.Logical) (interface{}, err
 	return nil, r.ResolveExpression(expr.Right)
 }
 
+func (r *Resolver) VisitConditionalExpr(expr *expressions.Conditional) (interface{}, error) {
+	if err := r.ResolveExpression(expr.Condition); err != nil {
+		return nil, err
+	}
+	if err := r.ResolveExpression(expr.ThenBranch); err != nil {
+		return nil, err
+	}
+	return nil, r.ResolveExpression(expr.ElseBranch)
+}
+
 func (r *Resolver) VisitUnaryExpr(expr *expressions.Unary) (interface{}, error) {
 	return nil, r.ResolveExpression(expr.Right)
 }
@@ -302,6 +577,23 @@ func (r *Resolver) VisitSetExpr(expr *expressions.Set) (interface{}, error) {
 	return nil, nil
 }
 
+func (r *Resolver) VisitIndexExpr(expr *expressions.Index) (interface{}, error) {
+	if err := r.ResolveExpression(expr.Object); err != nil {
+		return nil, err
+	}
+	return nil, r.ResolveExpression(expr.Index)
+}
+
+func (r *Resolver) VisitSetIndexExpr(expr *expressions.SetIndex) (interface{}, error) {
+	if err := r.ResolveExpression(expr.Value); err != nil {
+		return nil, err
+	}
+	if err := r.ResolveExpression(expr.Object); err != nil {
+		return nil, err
+	}
+	return nil, r.ResolveExpression(expr.Index)
+}
+
 func (r *Resolver) VisitClassStmt(stmt *expressions.Class) (interface{}, error) {
 	enclosingClass := r.CurrentClass
 	r.CurrentClass = ClassTypeClass
@@ -312,6 +604,26 @@ func (r *Resolver) VisitClassStmt(stmt *expressions.Class) (interface{}, error)
 
 	r.Define(stmt.Name)
 
+	for _, trait := range stmt.Traits {
+		if err := r.ResolveExpression(trait); err != nil {
+			return nil, err
+		}
+		if err := r.CheckImplementation(stmt, trait.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Static methods are resolved outside of the scope
+	// that holds this
+	for _, function := range stmt.StaticMethods {
+		if function.Name.Lexeme == interpreter.CLASS_CONSTRUCTOR_NAME {
+			return nil, r.Error(function.Name, "The constructor can not be static.")
+		}
+		if _, err := r.ResolveFunction(function, FunctionTypeStatic); err != nil {
+			return nil, err
+		}
+	}
+
 	// Begin scope
 	r.BeginScope()
 
@@ -320,6 +632,14 @@ func (r *Resolver) VisitClassStmt(stmt *expressions.Class) (interface{}, error)
 	// log.Print("These are scopes at the moment: ", r.Scopes)
 
 	for _, function := range stmt.Methods {
+		if function.Name.Lexeme == interpreter.CLASS_CONSTRUCTOR_NAME && function.Generator {
+			r.EndScope()
+			return nil, r.Error(function.Name, "The constructor can not yield.")
+		}
+		if function.Name.Lexeme == interpreter.CLASS_CONSTRUCTOR_NAME && function.Async {
+			r.EndScope()
+			return nil, r.Error(function.Name, "The constructor can not be async.")
+		}
 		declaration := FunctionTypeMethod
 		_, err := r.ResolveFunction(function, declaration)
 		if err != nil {
@@ -327,6 +647,23 @@ func (r *Resolver) VisitClassStmt(stmt *expressions.Class) (interface{}, error)
 		}
 	}
 
+	accessors := make([]*expressions.Function, 0, len(stmt.Getters)+len(stmt.Setters))
+	accessors = append(append(accessors, stmt.Getters...), stmt.Setters...)
+	for _, function := range accessors {
+		if function.Name.Lexeme == interpreter.CLASS_CONSTRUCTOR_NAME {
+			r.EndScope()
+			return nil, r.Error(function.Name, "The constructor can not be a getter or a setter.")
+		}
+		if function.Generator {
+			r.EndScope()
+			return nil, r.Error(function.Name, "A getter or a setter can not yield.")
+		}
+		if _, err := r.ResolveFunction(function, FunctionTypeMethod); err != nil {
+			r.EndScope()
+			return nil, err
+		}
+	}
+
 	r.EndScope()
 
 	r.CurrentClass = enclosingClass
@@ -334,12 +671,221 @@ func (r *Resolver) VisitClassStmt(stmt *expressions.Class) (interface{}, error)
 	return nil, nil
 }
 
+// VisitTraitStmt declares a trait, the methods of a trait
+// are only signatures and have nothing to resolve
+func (r *Resolver) VisitTraitStmt(stmt *expressions.Trait) (interface{}, error) {
+	if err := r.Declare(stmt.Name); err != nil {
+		return nil, err
+	}
+	r.Define(stmt.Name)
+
+	methods := make(map[string]bool)
+	for _, method := range stmt.Methods {
+		if methods[method.Name.Lexeme] {
+			return nil, r.Error(method.Name, "Method is already declared in trait "+stmt.Name.Lexeme+".")
+		}
+		methods[method.Name.Lexeme] = true
+	}
+
+	r.Traits[stmt.Name.Lexeme] = stmt
+	return nil, nil
+}
+
+// VisitEnumStmt declares an enum and checks that its variants
+// and the fields of each variant are not repeated
+func (r *Resolver) VisitEnumStmt(stmt *expressions.Enum) (interface{}, error) {
+	if err := r.Declare(stmt.Name); err != nil {
+		return nil, err
+	}
+	r.Define(stmt.Name)
+
+	variants := make(map[string]bool)
+	for index, variant := range stmt.Variants {
+		if variants[variant.Lexeme] {
+			return nil, r.Error(variant, "Variant is already declared in enum "+stmt.Name.Lexeme+".")
+		}
+		variants[variant.Lexeme] = true
+
+		fields := make(map[string]bool)
+		for _, field := range stmt.Fields[index] {
+			switch field.Lexeme {
+			case "name", "ordinal", "payload":
+				return nil, r.Error(field, "Can't use "+field.Lexeme+" as the name of a variant field.")
+			}
+			if fields[field.Lexeme] {
+				return nil, r.Error(field, "Field is already declared in variant "+variant.Lexeme+".")
+			}
+			fields[field.Lexeme] = true
+		}
+	}
+	return nil, nil
+}
+
+// CheckImplementation checks that a struct defines every method
+// of a trait it implements with the same number of parameters
+func (r *Resolver) CheckImplementation(class *expressions.Class, name token.Token) error {
+	trait, ok := r.Traits[name.Lexeme]
+	if !ok {
+		return r.Error(name, name.Lexeme+" is not a trait.")
+	}
+
+	methods := make(map[string]*expressions.Function)
+	for _, method := range class.Methods {
+		methods[method.Name.Lexeme] = method
+	}
+
+	for _, required := range trait.Methods {
+		method, ok := methods[required.Name.Lexeme]
+		if !ok {
+			return r.Error(class.Name, fmt.Sprintf("Struct %s does not implement method %s of trait %s.", class.Name.Lexeme, required.Name.Lexeme, trait.Name.Lexeme))
+		}
+		if len(method.Params) != len(required.Params) || method.Variadic != required.Variadic {
+			return r.Error(method.Name, fmt.Sprintf("Method %s of struct %s does not match the parameters of trait %s.", method.Name.Lexeme, class.Name.Lexeme, trait.Name.Lexeme))
+		}
+	}
+	return nil
+}
+
 func (r *Resolver) VisitThisExpr(expr *expressions.This) (interface{}, error) {
 	if r.CurrentClass == ClassTypeNone {
 		return nil, errors.New("Cannot use this keyword outside of the class")
 	}
+	if r.CurrentFunction == FunctionTypeStatic {
+		return nil, r.Error(expr.Keyword, "Can not use this in a static method.")
+	}
 
 	// log.Println("Resolving local this here")
 	r.ResolveLocal(expr, expr.Keyword)
 	return nil, nil
 }
+
+func (r *Resolver) VisitListLiteralExpr(expr *expressions.ListLiteral) (interface{}, error) {
+	for _, element := range expr.Elements {
+		if err := r.ResolveExpression(element); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitMapLiteralExpr(expr *expressions.MapLiteral) (interface{}, error) {
+	for index, key := range expr.Keys {
+		if err := r.ResolveExpression(key); err != nil {
+			return nil, err
+		}
+		if err := r.ResolveExpression(expr.Values[index]); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// VisitMatchStmt resolves every arm of a match in a scope of
+// its own holding the variables bound by its patterns
+func (r *Resolver) VisitMatchStmt(stmt *expressions.Match) (interface{}, error) {
+	if err := r.ResolveExpression(stmt.Value); err != nil {
+		return nil, err
+	}
+
+	unreachable := false
+	for _, arm := range stmt.Arms {
+		if unreachable {
+			r.Warn(arm.Arrow, "Unreachable match arm, an earlier arm matches every value")
+		}
+
+		r.BeginScope()
+		for _, pattern := range arm.Patterns {
+			if err := r.ResolvePattern(pattern); err != nil {
+				r.EndScope()
+				return nil, err
+			}
+		}
+		if len(arm.Patterns) > 1 && len(r.Scopes[len(r.Scopes)-1]) > 0 {
+			r.EndScope()
+			return nil, r.Error(arm.Arrow, "Alternative patterns can not bind variables.")
+		}
+
+		if arm.Guard != nil {
+			if err := r.ResolveExpression(arm.Guard); err != nil {
+				r.EndScope()
+				return nil, err
+			}
+		}
+		err := r.ResolveStatement(arm.Body)
+		r.EndScope()
+		if err != nil {
+			return nil, err
+		}
+
+		if arm.Guard == nil && r.IsIrrefutable(arm.Patterns) {
+			unreachable = true
+		}
+	}
+
+	return nil, nil
+}
+
+// IsIrrefutable checks if one of the patterns matches any value
+func (r *Resolver) IsIrrefutable(patterns []expressions.Pattern) bool {
+	for _, pattern := range patterns {
+		switch pattern.(type) {
+		case *expressions.Wildcard, *expressions.Binding:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resolver) VisitConstantPattern(pattern *expressions.Constant) (interface{}, error) {
+	return nil, nil
+}
+
+func (r *Resolver) VisitWildcardPattern(pattern *expressions.Wildcard) (interface{}, error) {
+	return nil, nil
+}
+
+func (r *Resolver) VisitBindingPattern(pattern *expressions.Binding) (interface{}, error) {
+	if err := r.Declare(pattern.Name); err != nil {
+		return nil, err
+	}
+	if r.DeclaringConstant {
+		r.DeclareConstant(pattern.Name)
+	}
+	r.Define(pattern.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitSequencePattern(pattern *expressions.Sequence) (interface{}, error) {
+	for _, element := range pattern.Elements {
+		if err := r.ResolvePattern(element); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitRecordPattern(pattern *expressions.Record) (interface{}, error) {
+	if pattern.Class != nil {
+		if err := r.ResolveExpression(pattern.Class); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range pattern.Patterns {
+		if err := r.ResolvePattern(field); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitVariantPattern(pattern *expressions.Variant) (interface{}, error) {
+	if err := r.ResolveExpression(pattern.Enum); err != nil {
+		return nil, err
+	}
+	for _, element := range pattern.Patterns {
+		if err := r.ResolvePattern(element); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
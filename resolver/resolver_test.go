@@ -0,0 +1,159 @@
+package resolver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Atul-Ranjan12/lang"
+)
+
+func TestConstants(t *testing.T) {
+	valid := []string{
+		"const A = 1; println A;",
+		"const [A, B] = [1, 2]; println A + B;",
+		// A local of the same name can be assigned
+		"def f() { var A = 0; A = 2; return A; } const A = 1;",
+		"const A = 1; def f(A) { A = 2; }",
+	}
+	for _, source := range valid {
+		if _, err := lang.Compile(source); err != nil {
+			t.Errorf("%s: %v", source, err)
+		}
+	}
+
+	invalid := []string{
+		"const A = 1; A = 2;",
+		"const A = 1; const A = 2;",
+		"const [A, B] = [1, 2]; B = 3;",
+		"{ const A = 1; A = 2; }",
+		// A function declared before the constant
+		"def f() { A = 2; } const A = 1; f();",
+		"def f() { B = 2; } const {x: A, y: B} = {x: 1, y: 2};",
+	}
+	for _, source := range invalid {
+		_, err := lang.Compile(source)
+		if err == nil || !strings.Contains(err.Error(), "constant") {
+			t.Errorf("%s: expected an error about a constant but got %v", source, err)
+		}
+	}
+}
+
+func TestMatchArms(t *testing.T) {
+	program, err := lang.Compile(`
+match (1) {
+  n => println n;
+  _ => println "never";
+  2 if true => println "never";
+}
+match (2) {
+  n if n > 1 => println n;
+  _ => println "reachable";
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(program.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings but got %v", program.Warnings)
+	}
+	for _, warning := range program.Warnings {
+		if !strings.Contains(warning, "Unreachable match arm") || !strings.Contains(warning, "[line ") {
+			t.Errorf("got the warning %q", warning)
+		}
+	}
+
+	invalid := []struct {
+		source string
+		want   string
+	}{
+		{"match (1) { 1, n => println n; }", "Alternative patterns can not bind variables."},
+		{"match ([1, 2]) { [a, a] => println a; }", "Already a variable with this name in this scope."},
+	}
+	for _, test := range invalid {
+		_, err := lang.Compile(test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestDestructuringDeclarations(t *testing.T) {
+	invalid := []string{
+		"{ var [a, a] = [1, 2]; }",
+		"{ var a = 1; var {a} = {a: 2}; }",
+		"def f(a) { var [a, b] = [1, 2]; }",
+	}
+	for _, source := range invalid {
+		_, err := lang.Compile(source)
+		if err == nil || !strings.Contains(err.Error(), "Already a variable with this name in this scope.") {
+			t.Errorf("%s: expected a redeclaration error but got %v", source, err)
+		}
+	}
+}
+
+func TestTraitImplementations(t *testing.T) {
+	valid := []string{
+		"trait T { f(a); } struct S impl T { construct() {} f(b) {} }",
+		"trait T { f(...xs); } struct S impl T { construct() {} f(...ys) {} }",
+		"trait T { } struct S impl T { construct() {} }",
+	}
+	for _, source := range valid {
+		if _, err := lang.Compile(source); err != nil {
+			t.Errorf("%s: %v", source, err)
+		}
+	}
+
+	invalid := []struct {
+		source string
+		want   string
+	}{
+		{"trait T { f(); g(); } struct S impl T { construct() {} f() {} }", "Struct S does not implement method g of trait T."},
+		{"trait T { f(a); } struct S impl T { construct() {} f() {} }", "Method f of struct S does not match the parameters of trait T."},
+		{"trait T { f(...a); } struct S impl T { construct() {} f(a) {} }", "Method f of struct S does not match the parameters of trait T."},
+		{"var T = 1; struct S impl T { construct() {} }", "T is not a trait."},
+		{"struct S impl T { construct() {} } trait T { f(); }", "T is not a trait."},
+	}
+	for _, test := range invalid {
+		_, err := lang.Compile(test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestStaticMethodsAndAccessors(t *testing.T) {
+	invalid := []struct {
+		source string
+		want   string
+	}{
+		{"struct P { static construct() {} }", "The constructor can not be static."},
+		{"struct P { construct() {} static f() { return this; } }", "Can not use this in a static method."},
+		{"struct P { get construct() { return 1; } }", "The constructor can not be a getter or a setter."},
+		{"struct P { construct() {} get a() { yield 1; } }", "A getter or a setter can not yield."},
+		{"struct P { construct() {} get a(x) { return 1; } }", "A getter can not have parameters"},
+		{"struct P { construct() {} set a() {} }", "A setter must have exactly one parameter"},
+	}
+	for _, test := range invalid {
+		_, err := lang.Compile(test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestEnumDeclarations(t *testing.T) {
+	invalid := []struct {
+		source string
+		want   string
+	}{
+		{"enum E { A, A }", "Variant is already declared in enum E."},
+		{"enum E { A(x, x) }", "Field is already declared in variant A."},
+		{"enum E { A(name) }", "Can't use name as the name of a variant field."},
+	}
+	for _, test := range invalid {
+		_, err := lang.Compile(test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want an error containing %q", test.source, err, test.want)
+		}
+	}
+}
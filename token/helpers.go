@@ -10,6 +10,10 @@ func TokenTypeToString(tt TokenType) string {
 		return "LEFT_BRACE"
 	case RIGHT_BRACE:
 		return "RIGHT_BRACE"
+	case LEFT_BRACKET:
+		return "LEFT_BRACKET"
+	case RIGHT_BRACKET:
+		return "RIGHT_BRACKET"
 	case COMMA:
 		return "COMMA"
 	case DOT:
@@ -20,10 +24,38 @@ func TokenTypeToString(tt TokenType) string {
 		return "PLUS"
 	case SEMICOLON:
 		return "SEMICOLON"
+	case COLON:
+		return "COLON"
+	case QUESTION:
+		return "QUESTION"
 	case SLASH:
 		return "SLASH"
 	case STAR:
 		return "STAR"
+	case AMPERSAND:
+		return "AMPERSAND"
+	case PIPE:
+		return "PIPE"
+	case CARET:
+		return "CARET"
+	case TILDE:
+		return "TILDE"
+	case SLASH_SLASH:
+		return "SLASH_SLASH"
+	case STAR_STAR:
+		return "STAR_STAR"
+	case LESS_LESS:
+		return "LESS_LESS"
+	case GREATER_GREATER:
+		return "GREATER_GREATER"
+	case QUESTION_QUESTION:
+		return "QUESTION_QUESTION"
+	case QUESTION_DOT:
+		return "QUESTION_DOT"
+	case ARROW:
+		return "ARROW"
+	case ELLIPSIS:
+		return "ELLIPSIS"
 	case BANG:
 		return "BANG"
 	case BANG_EQUAL:
@@ -48,10 +80,18 @@ func TokenTypeToString(tt TokenType) string {
 		return "NUMBER"
 	case AND:
 		return "AND"
+	case ASYNC:
+		return "ASYNC"
+	case AWAIT:
+		return "AWAIT"
 	case CLASS:
 		return "CLASS"
+	case CONST:
+		return "CONST"
 	case ELSE:
 		return "ELSE"
+	case ENUM:
+		return "ENUM"
 	case FALSE:
 		return "FALSE"
 	case FUN:
@@ -60,6 +100,10 @@ func TokenTypeToString(tt TokenType) string {
 		return "FOR"
 	case IF:
 		return "IF"
+	case IMPL:
+		return "IMPL"
+	case MATCH:
+		return "MATCH"
 	case NIL:
 		return "NIL"
 	case OR:
@@ -68,16 +112,24 @@ func TokenTypeToString(tt TokenType) string {
 		return "PRINT"
 	case RETURN:
 		return "RETURN"
+	case SELECT:
+		return "SELECT"
+	case SPAWN:
+		return "SPAWN"
 	case SUPER:
 		return "SUPER"
 	case THIS:
 		return "THIS"
+	case TRAIT:
+		return "TRAIT"
 	case TRUE:
 		return "TRUE"
 	case VAR:
 		return "VAR"
 	case WHILE:
 		return "WHILE"
+	case YIELD:
+		return "YIELD"
 	case EOF:
 		return "EOF"
 	default:
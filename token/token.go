@@ -7,22 +7,32 @@ import (
 // These are the standard Keywords for the language
 var Keywords = map[string]TokenType{
 	"and":     AND,
+	"async":   ASYNC,
+	"await":   AWAIT,
 	"break":   BREAK,
+	"const":   CONST,
 	"struct":  CLASS,
 	"else":    ELSE,
+	"enum":    ENUM,
 	"false":   FALSE,
 	"for":     FOR,
 	"def":     FUN,
 	"if":      IF,
+	"impl":    IMPL,
+	"match":   MATCH,
 	"nil":     NIL,
 	"or":      OR,
 	"println": PRINT,
 	"return":  RETURN,
+	"select":  SELECT,
+	"spawn":   SPAWN,
 	"super":   SUPER,
 	"this":    THIS,
+	"trait":   TRAIT,
 	"true":    TRUE,
 	"var":     VAR,
 	"while":   WHILE,
+	"yield":   YIELD,
 }
 
 // Token represents a token in the source code
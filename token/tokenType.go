@@ -8,14 +8,34 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
 	COMMA
 	DOT
 	MINUS
 	PLUS
 	SEMICOLON
+	COLON
+	QUESTION
 	SLASH
 	STAR
 
+	// Bitwise operators
+	AMPERSAND
+	PIPE
+	CARET
+	TILDE
+
+	// Two character operators
+	SLASH_SLASH
+	STAR_STAR
+	LESS_LESS
+	GREATER_GREATER
+	QUESTION_QUESTION
+	QUESTION_DOT
+	ARROW
+	ELLIPSIS
+
 	// One or two character tokens
 	BANG
 	BANG_EQUAL
@@ -33,22 +53,32 @@ const (
 
 	// Keywords
 	AND
+	ASYNC
+	AWAIT
 	CLASS
 	BREAK
+	CONST
 	ELSE
+	ENUM
 	FALSE
 	FUN
 	FOR
 	IF
+	IMPL
+	MATCH
 	NIL
 	OR
 	PRINT
 	RETURN
+	SELECT
+	SPAWN
 	SUPER
 	THIS
+	TRAIT
 	TRUE
 	VAR
 	WHILE
+	YIELD
 
 	EOF
 )